@@ -0,0 +1,216 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// accessRequestPollInterval is how often a create waiting for approval
+// checks the request.
+var accessRequestPollInterval = 10 * time.Second
+
+func ResourceAdaptiveAccessRequest() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: ResourceAdaptiveAccessRequestCreate,
+		ReadContext:   ResourceAdaptiveAccessRequestRead,
+		UpdateContext: ResourceAdaptiveAccessRequestUpdate,
+		DeleteContext: ResourceAdaptiveAccessRequestDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"endpoint": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the JIT-enabled adaptive_endpoint to request access to.",
+			},
+			"requester": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Email of the user the access is for. Defaults to the owner of the provider's service token.",
+			},
+			"duration": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateApprovalDuration,
+				DiffSuppressFunc: suppressEquivalentDuration,
+				Description:      "How long the access lasts once approved, such as 1h or 1d. Capped by the endpoint's max_grant_duration; `granted_duration` reports the result.",
+			},
+			"justification": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Why the access is needed, shown to approvers. Required by approval policies with require_justification.",
+			},
+			"ticket_reference": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Incident or change ticket the request relates to, such as INC-1234.",
+			},
+			"wait_for_approval": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether create waits, up to the create timeout, until the request is approved. A denied or expired request then fails the apply. When false, create returns as soon as the request is submitted.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the request. One of: " + strings.Join(accessRequestStatuses, ", ") + ".",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "RFC3339 time the grant ends, or, while the request is pending, the time it expires unapproved.",
+			},
+			"granted_duration": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Duration the backend granted, which is `duration` capped by the endpoint's max_grant_duration.",
+			},
+		},
+	}
+}
+
+var accessRequestStatuses = []string{
+	adaptive.AccessRequestPending,
+	adaptive.AccessRequestApproved,
+	adaptive.AccessRequestDenied,
+	adaptive.AccessRequestExpired,
+	adaptive.AccessRequestRevoked,
+}
+
+func ResourceAdaptiveAccessRequestCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	duration := approvalDurationValue(d.Get("duration").(string))
+	resp, err := client.CreateAccessRequest(ctx, &adaptive.AccessRequest{
+		Endpoint:        d.Get("endpoint").(string),
+		Requester:       d.Get("requester").(string),
+		Duration:        duration,
+		Justification:   d.Get("justification").(string),
+		TicketReference: d.Get("ticket_reference").(string),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(resp.ID)
+	if err := setAccessRequestState(d, resp); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !d.Get("wait_for_approval").(bool) || resp.Status != adaptive.AccessRequestPending {
+		return accessRequestOutcome(resp, d.Get("wait_for_approval").(bool))
+	}
+
+	timeout := d.Timeout(schema.TimeoutCreate)
+	resp, err = client.WaitForAccessRequest(ctx, resp.ID, timeout, accessRequestPollInterval)
+	if errors.Is(err, adaptive.ErrTimeout) {
+		// The request is kept in state but tainted, so the next apply revokes
+		// it and requests again.
+		return diag.Errorf("access request %s to endpoint %q is still pending after %s. Raise the create timeout to wait longer", d.Id(), d.Get("endpoint").(string), timeout)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := setAccessRequestState(d, resp); err != nil {
+		return diag.FromErr(err)
+	}
+	return accessRequestOutcome(resp, true)
+}
+
+// accessRequestOutcome reports a request that will not be granted. A pending
+// request is only an error when the caller waited for it.
+func accessRequestOutcome(resp *adaptive.AccessRequestResponse, waited bool) diag.Diagnostics {
+	switch resp.Status {
+	case adaptive.AccessRequestApproved:
+		return nil
+	case adaptive.AccessRequestPending:
+		if !waited {
+			return nil
+		}
+	}
+	reason := ""
+	if resp.Reason != "" {
+		reason = ": " + resp.Reason
+	}
+	return diag.Errorf("access request %s to endpoint %q was %s%s", resp.ID, resp.Endpoint, resp.Status, reason)
+}
+
+func ResourceAdaptiveAccessRequestRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	resp, err := client.GetAccessRequest(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Request was deleted out-of-band — drop it from state. Ended grants are
+	// kept, with their status, so Terraform does not silently request again.
+	if resp == nil {
+		d.SetId("")
+		return nil
+	}
+	if err := setAccessRequestState(d, resp); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// setAccessRequestState refreshes the computed attributes from a response.
+// The inputs force a new request, so they are only filled in when state has
+// none, as after an import: the backend may normalize or cap them, as it does
+// duration, and writing that back would plan a replacement on every apply.
+func setAccessRequestState(d *schema.ResourceData, resp *adaptive.AccessRequestResponse) error {
+	values := map[string]interface{}{
+		"status":           resp.Status,
+		"expires_at":       resp.ExpiresAt,
+		"granted_duration": resp.Duration,
+	}
+	for key, v := range map[string]string{
+		"endpoint":         resp.Endpoint,
+		"requester":        resp.Requester,
+		"duration":         resp.Duration,
+		"justification":    resp.Justification,
+		"ticket_reference": resp.TicketReference,
+	} {
+		if v != "" && d.Get(key).(string) == "" {
+			values[key] = v
+		}
+	}
+	for key, v := range values {
+		if err := d.Set(key, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResourceAdaptiveAccessRequestUpdate only handles wait_for_approval, the one
+// attribute that does not force a new request. It has no effect after create.
+func ResourceAdaptiveAccessRequestUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return ResourceAdaptiveAccessRequestRead(ctx, d, m)
+}
+
+func ResourceAdaptiveAccessRequestDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	if err := client.RevokeAccessRequest(ctx, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,161 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// accessRequestServer serves an access request that stays pending for the
+// given number of reads, then reports final.
+func accessRequestServer(t *testing.T, pendingReads int32, final string, body *adaptive.AccessRequest) (*httptest.Server, *int32) {
+	var reads int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		status := adaptive.AccessRequestPending
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/terraform/access-request/create"):
+			if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+				t.Errorf("decoding request body: %v", err)
+			}
+		case strings.HasSuffix(r.URL.Path, "/terraform/access-request/read/req-1"):
+			if atomic.AddInt32(&reads, 1) > pendingReads {
+				status = final
+			}
+		default:
+			http.Error(w, "unexpected path "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "req-1", "endpoint": "prod-db", "requester": "oncall@example.com", "duration": "1h", "status": %q, "expiresAt": "2026-10-18T13:00:00Z", "reason": "not on call"}`, status)
+	}))
+	return srv, &reads
+}
+
+func TestResourceAdaptiveAccessRequestCreate(t *testing.T) {
+	defer func(d time.Duration) { accessRequestPollInterval = d }(accessRequestPollInterval)
+	accessRequestPollInterval = time.Millisecond
+
+	tests := []struct {
+		name    string
+		wait    bool
+		final   string
+		status  string
+		wantErr string
+	}{
+		{name: "no wait", final: adaptive.AccessRequestApproved, status: adaptive.AccessRequestPending},
+		{name: "approved", wait: true, final: adaptive.AccessRequestApproved, status: adaptive.AccessRequestApproved},
+		{name: "denied", wait: true, final: adaptive.AccessRequestDenied, status: adaptive.AccessRequestDenied, wantErr: "was denied: not on call"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent adaptive.AccessRequest
+			srv, _ := accessRequestServer(t, 2, tt.final, &sent)
+			defer srv.Close()
+
+			d := schema.TestResourceDataRaw(t, ResourceAdaptiveAccessRequest().Schema, map[string]interface{}{
+				"endpoint":          "prod-db",
+				"duration":          "60m",
+				"justification":     "INC-1234 database failover",
+				"ticket_reference":  "INC-1234",
+				"wait_for_approval": tt.wait,
+			})
+			diags := ResourceAdaptiveAccessRequestCreate(context.Background(), d, adaptive.NewClient("test-token", srv.URL))
+
+			if sent.Duration != "1h" || sent.TicketReference != "INC-1234" {
+				t.Errorf("sent %+v, want duration 1h and the ticket reference", sent)
+			}
+			if d.Id() != "req-1" || d.Get("status").(string) != tt.status || d.Get("expires_at").(string) == "" {
+				t.Errorf("state: id %q, status %q, expires_at %q", d.Id(), d.Get("status"), d.Get("expires_at"))
+			}
+			if tt.wantErr == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %+v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || !strings.Contains(diags[0].Summary, tt.wantErr) {
+				t.Fatalf("diagnostics = %+v, want an error containing %q", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForAccessRequest(t *testing.T) {
+	var sent adaptive.AccessRequest
+	srv, reads := accessRequestServer(t, 1000, adaptive.AccessRequestApproved, &sent)
+	defer srv.Close()
+	client := adaptive.NewClient("test-token", srv.URL)
+
+	_, err := client.WaitForAccessRequest(context.Background(), "req-1", 30*time.Millisecond, time.Millisecond)
+	if !errors.Is(err, adaptive.ErrTimeout) {
+		t.Errorf("error = %v, want a timeout", err)
+	}
+	if atomic.LoadInt32(reads) < 2 {
+		t.Errorf("expected the request to be polled, got %d reads", atomic.LoadInt32(reads))
+	}
+
+	_, err = client.WaitForAccessRequest(context.Background(), "req-2", time.Minute, time.Millisecond)
+	if !errors.Is(err, adaptive.ErrAccessRequestGone) {
+		t.Errorf("error = %v, want ErrAccessRequestGone without waiting for the timeout", err)
+	}
+}
+
+func TestResourceAdaptiveAccessRequestRead_Gone(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveAccessRequest().Schema, map[string]interface{}{
+		"endpoint": "prod-db", "duration": "1h",
+	})
+	d.SetId("req-1")
+	if diags := ResourceAdaptiveAccessRequestRead(context.Background(), d, adaptive.NewClient("test-token", srv.URL)); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected a deleted request to drop out of state, got ID %q", d.Id())
+	}
+}
+
+// The backend caps duration at the endpoint's max_grant_duration. Writing the
+// capped value back into the ForceNew input would replace the request on every
+// apply, so it is reported as granted_duration instead.
+func TestResourceAdaptiveAccessRequestRead_CappedDuration(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "req-1", "endpoint": "prod-db", "requester": "oncall@example.com", "duration": "4h", "status": "approved", "justification": "failover."}`))
+	}))
+	defer srv.Close()
+
+	res := ResourceAdaptiveAccessRequest()
+	cfg := map[string]interface{}{"endpoint": "prod-db", "duration": "8h", "justification": "failover"}
+	d := schema.TestResourceDataRaw(t, res.Schema, cfg)
+	d.SetId("req-1")
+	if diags := ResourceAdaptiveAccessRequestRead(context.Background(), d, adaptive.NewClient("test-token", srv.URL)); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+	if d.Get("duration") != "8h" || d.Get("granted_duration") != "4h" || d.Get("justification") != "failover" {
+		t.Errorf("duration %q, granted_duration %q, justification %q", d.Get("duration"), d.Get("granted_duration"), d.Get("justification"))
+	}
+	if got := d.Get("requester"); got != "oncall@example.com" {
+		t.Errorf("requester = %q, want the resolved default", got)
+	}
+
+	diff, err := res.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(cfg), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && diff.RequiresNew() {
+		t.Errorf("refreshed request plans a replacement: %+v", diff.Attributes)
+	}
+}
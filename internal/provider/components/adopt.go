@@ -0,0 +1,53 @@
+package components
+
+import (
+	"fmt"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// adoptExistingSchema is shared by every resource whose create can adopt an
+// object left behind by a partially failed apply.
+func adoptExistingSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: fmt.Sprintf("When the %s name is already taken, adopt the existing %s into state and update it to match this configuration instead of failing. Only consulted on create. Needs an Adaptive backend that can look %s up by name (GET /terraform/<type>/find/<name>), which current backends cannot; against them adoption fails with an error. Defaults to the provider's `adopt_existing`.", kind, kind, kind+"s"),
+	}
+}
+
+// shouldAdoptExisting reports whether create should adopt on a duplicate
+// name. An explicit adopt_existing on the resource wins over the provider
+// default, so the raw config is read to tell "unset" from "false".
+func shouldAdoptExisting(d *schema.ResourceData, client *adaptive.Client) bool {
+	raw := d.GetRawConfig()
+	if !raw.IsNull() && raw.IsKnown() {
+		if v := raw.GetAttr("adopt_existing"); !v.IsNull() && v.IsKnown() && v.Type() == cty.Bool {
+			return v.True()
+		}
+	}
+	if v, ok := d.GetOk("adopt_existing"); ok {
+		return v.(bool)
+	}
+	return client.Options.AdoptExisting
+}
+
+// adoptedWarning tells the user an existing object was taken over rather
+// than created.
+func adoptedWarning(kind, name, id string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Adopted existing %s %q", kind, name),
+		Detail:   fmt.Sprintf("A %s named %q already existed (id %s). It was adopted into state and updated to match this configuration because adopt_existing is enabled.", kind, name, id),
+	}
+}
+
+// adoptNotFoundError reports a failed adoption lookup. Create only adopts after
+// the backend rejected the name as taken, so a lookup that finds nothing
+// usually means the backend has no find route rather than no such object.
+func adoptNotFoundError(kind, name string, createErr error) error {
+	return fmt.Errorf("%w; adopt_existing could not find the existing %s %q: it needs a backend that serves GET /terraform/<type>/find/<name>, and current backends return 404 for it", createErr, kind, name)
+}
@@ -0,0 +1,372 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adaptive-scale/terraform-provider-adaptive/internal/provider/integrations"
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// maxApprovalStages bounds the number of stages of an approval policy.
+const maxApprovalStages = 5
+
+// approvalPolicySchema returns the rules of an approval policy, shared by
+// the adaptive_approval_policy resource and the approval_policy block of
+// adaptive_endpoint.
+func approvalPolicySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"stage": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MinItems:    1,
+			MaxItems:    maxApprovalStages,
+			Description: fmt.Sprintf("An approval level. Stages are approved in order, and a request is granted once every stage is approved. Up to %d stages.", maxApprovalStages),
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"approver_users": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotEmpty},
+						Description: "Emails of users who can approve this stage.",
+					},
+					"approver_groups": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotEmpty},
+						Description: "Names of groups whose members can approve this stage.",
+					},
+					"min_approvals": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Default:      1,
+						ValidateFunc: validation.IntAtLeast(1),
+						Description:  "Approvals this stage needs from its approvers.",
+					},
+				},
+			},
+		},
+		"allow_self_approval": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Whether requesters may approve their own requests. When false, a requester's approval does not count towards any stage.",
+		},
+		"require_justification": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Whether requests must include a justification.",
+		},
+		"justification_min_length": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      0,
+			ValidateFunc: validation.IntBetween(0, 1000),
+			Description:  "Shortest justification accepted, in characters. Requires require_justification.",
+		},
+		"max_grant_duration": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			ValidateFunc:     validateApprovalDuration,
+			DiffSuppressFunc: suppressEquivalentDuration,
+			Description:      "Longest access an approval grants, such as 4h or 7d. If not set, access lasts until the endpoint's ttl.",
+		},
+		"pending_request_expiry": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			ValidateFunc:     validateApprovalDuration,
+			DiffSuppressFunc: suppressEquivalentDuration,
+			Description:      "How long a request waits for approval before it expires, such as 30m or 1d. If not set, pending requests do not expire.",
+		},
+	}
+}
+
+// validateApprovalDuration accepts the endpoint duration notation, such as
+// 90m, 4h or 7d. Unlike endpoint timeouts, "never" is not accepted; leave the
+// attribute unset instead.
+func validateApprovalDuration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s must be a string", k)}
+	}
+	if v == "" {
+		return nil, nil
+	}
+	_, never, err := parseEndpointDuration(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	if never {
+		return nil, []error{fmt.Errorf("%s cannot be %q; leave it unset for no limit", k, v)}
+	}
+	return nil, nil
+}
+
+// approvalDurationValue renders a duration in the backend's notation.
+func approvalDurationValue(s string) string {
+	d, never, err := parseEndpointDuration(s)
+	if s == "" || never || err != nil {
+		return s
+	}
+	return formatEndpointDuration(d)
+}
+
+// approvalPolicyFromSchema reads the rules of an approval policy. prefix is
+// "" for adaptive_approval_policy and "approval_policy.0." for the endpoint
+// block.
+func approvalPolicyFromSchema(d integrations.SchemaGetter, prefix string) *adaptive.ApprovalPolicy {
+	p := &adaptive.ApprovalPolicy{
+		AllowSelfApproval:      d.Get(prefix + "allow_self_approval").(bool),
+		RequireJustification:   d.Get(prefix + "require_justification").(bool),
+		JustificationMinLength: d.Get(prefix + "justification_min_length").(int),
+		MaxGrantDuration:       approvalDurationValue(d.Get(prefix + "max_grant_duration").(string)),
+		PendingRequestExpiry:   approvalDurationValue(d.Get(prefix + "pending_request_expiry").(string)),
+		Stages:                 []adaptive.ApprovalStage{},
+	}
+	stages, _ := d.Get(prefix + "stage").([]interface{})
+	for _, raw := range stages {
+		stage, _ := raw.(map[string]interface{})
+		if stage == nil {
+			stage = map[string]interface{}{}
+		}
+		minApprovals, _ := stage["min_approvals"].(int)
+		p.Stages = append(p.Stages, adaptive.ApprovalStage{
+			ApproverUsers:  interfaceStrings(stage["approver_users"]),
+			ApproverGroups: interfaceStrings(stage["approver_groups"]),
+			MinApprovals:   minApprovals,
+		})
+	}
+	return p
+}
+
+func interfaceStrings(raw interface{}) []string {
+	list, _ := raw.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func flattenApprovalStages(stages []adaptive.ApprovalStage) []interface{} {
+	out := make([]interface{}, 0, len(stages))
+	for _, s := range stages {
+		out = append(out, map[string]interface{}{
+			"approver_users":  s.ApproverUsers,
+			"approver_groups": s.ApproverGroups,
+			"min_approvals":   s.MinApprovals,
+		})
+	}
+	return out
+}
+
+// checkApprovalPolicy checks the rules of an approval policy: every stage has
+// approvers, none is listed twice in a stage, and a stage approved only by
+// users can reach its min_approvals.
+func checkApprovalPolicy(p *adaptive.ApprovalPolicy) error {
+	var errs []error
+	for i, s := range p.Stages {
+		n := i + 1
+		if len(s.ApproverUsers) == 0 && len(s.ApproverGroups) == 0 {
+			errs = append(errs, fmt.Errorf("stage %d has no approver_users or approver_groups", n))
+			continue
+		}
+		for _, approvers := range []struct {
+			attr string
+			list []string
+		}{{"approver_users", s.ApproverUsers}, {"approver_groups", s.ApproverGroups}} {
+			seen := map[string]bool{}
+			for _, v := range approvers.list {
+				if seen[v] {
+					errs = append(errs, fmt.Errorf("stage %d lists %q in %s more than once", n, v, approvers.attr))
+				}
+				seen[v] = true
+			}
+		}
+		if len(s.ApproverGroups) > 0 {
+			// Group sizes are not known at plan time.
+			continue
+		}
+		if s.MinApprovals > len(s.ApproverUsers) {
+			errs = append(errs, fmt.Errorf("stage %d needs %d approvals but has %d approver_users", n, s.MinApprovals, len(s.ApproverUsers)))
+		}
+	}
+	if p.JustificationMinLength > 0 && !p.RequireJustification {
+		errs = append(errs, errors.New("justification_min_length requires require_justification = true"))
+	}
+	return errors.Join(errs...)
+}
+
+// customizeDiffApprovalPolicy enforces checkApprovalPolicy at plan time, once
+// every rule is known, including approvers that come from other resources.
+// prefix is as for approvalPolicyFromSchema.
+func customizeDiffApprovalPolicy(d *schema.ResourceDiff, prefix string) error {
+	keys := make([]string, 0, len(approvalPolicySchema()))
+	for k := range approvalPolicySchema() {
+		keys = append(keys, prefix+k)
+	}
+	if !blockValuesKnown(d, keys...) {
+		return nil
+	}
+	return checkApprovalPolicy(approvalPolicyFromSchema(d, prefix))
+}
+
+// endpointApprovalFromSchema returns the approval policy of an endpoint: a
+// shared policy by name, or the inline approval_policy block.
+func endpointApprovalFromSchema(d integrations.SchemaGetter) adaptive.EndpointApproval {
+	if name, _ := d.Get("approval_policy_name").(string); name != "" {
+		return adaptive.EndpointApproval{ApprovalPolicyName: name}
+	}
+	if block, _ := d.Get("approval_policy").([]interface{}); len(block) > 0 {
+		return adaptive.EndpointApproval{ApprovalPolicy: approvalPolicyFromSchema(d, "approval_policy.0.")}
+	}
+	return adaptive.EndpointApproval{}
+}
+
+// customizeDiffEndpointApproval checks an endpoint's inline approval policy,
+// and that an approval policy is only set on JIT endpoints.
+func customizeDiffEndpointApproval(d *schema.ResourceDiff) error {
+	if !newValuesKnown(d, "is_jit_enabled", "approval_policy", "approval_policy_name") {
+		return nil
+	}
+	block, _ := d.Get("approval_policy").([]interface{})
+	name, _ := d.Get("approval_policy_name").(string)
+	if (len(block) > 0 || name != "") && !d.Get("is_jit_enabled").(bool) {
+		return errors.New("an approval policy only applies to JIT access; set is_jit_enabled = true")
+	}
+	if len(block) == 0 {
+		return nil
+	}
+	return customizeDiffApprovalPolicy(d, "approval_policy.0.")
+}
+
+func ResourceAdaptiveApprovalPolicy() *schema.Resource {
+	s := approvalPolicySchema()
+	s["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "Name of the approval policy. Must be unique within the workspace; endpoints reference the policy by it.",
+	}
+	s["description"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Description of the approval policy.",
+	}
+	return &schema.Resource{
+		CreateContext: ResourceAdaptiveApprovalPolicyCreate,
+		ReadContext:   ResourceAdaptiveApprovalPolicyRead,
+		UpdateContext: ResourceAdaptiveApprovalPolicyUpdate,
+		DeleteContext: ResourceAdaptiveApprovalPolicyDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			return customizeDiffApprovalPolicy(d, "")
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: s,
+	}
+}
+
+func approvalPolicyRequestFromSchema(d *schema.ResourceData) *adaptive.ApprovalPolicy {
+	p := approvalPolicyFromSchema(d, "")
+	p.Name = d.Get("name").(string)
+	p.Description = d.Get("description").(string)
+	return p
+}
+
+func ResourceAdaptiveApprovalPolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	resp, err := client.CreateApprovalPolicy(ctx, approvalPolicyRequestFromSchema(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(resp.ID)
+	return nil
+}
+
+func ResourceAdaptiveApprovalPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	resp, err := client.GetApprovalPolicy(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Policy was deleted out-of-band — drop it from state so Terraform recreates it.
+	if resp == nil {
+		d.SetId("")
+		return nil
+	}
+	if err := setApprovalPolicyState(d, resp); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func setApprovalPolicyState(d *schema.ResourceData, resp *adaptive.ApprovalPolicyResponse) error {
+	values := flattenApprovalPolicy(&resp.ApprovalPolicy)
+	values["name"] = resp.Name
+	values["description"] = resp.Description
+	for key, v := range values {
+		if err := d.Set(key, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenApprovalPolicy returns the rules of an approval policy keyed by
+// their attribute names, the inverse of approvalPolicyFromSchema.
+func flattenApprovalPolicy(p *adaptive.ApprovalPolicy) map[string]interface{} {
+	return map[string]interface{}{
+		"stage":                    flattenApprovalStages(p.Stages),
+		"allow_self_approval":      p.AllowSelfApproval,
+		"require_justification":    p.RequireJustification,
+		"justification_min_length": p.JustificationMinLength,
+		"max_grant_duration":       p.MaxGrantDuration,
+		"pending_request_expiry":   p.PendingRequestExpiry,
+	}
+}
+
+// setEndpointApprovalState refreshes an endpoint's approval_policy_name and
+// approval_policy block from what the backend reports.
+func setEndpointApprovalState(d *schema.ResourceData, approval *adaptive.EndpointApproval) error {
+	if err := d.Set("approval_policy_name", approval.ApprovalPolicyName); err != nil {
+		return err
+	}
+	var block []interface{}
+	// A policy attached by name is reported with its rules as well; only an
+	// inline policy belongs in the block.
+	if approval.ApprovalPolicy != nil && approval.ApprovalPolicyName == "" {
+		block = []interface{}{flattenApprovalPolicy(approval.ApprovalPolicy)}
+	}
+	return d.Set("approval_policy", block)
+}
+
+func ResourceAdaptiveApprovalPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	if _, err := client.UpdateApprovalPolicy(ctx, d.Id(), approvalPolicyRequestFromSchema(d)); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func ResourceAdaptiveApprovalPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	if err := client.DeleteApprovalPolicy(ctx, d.Id(), d.Get("name").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,234 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestApprovalPolicyFromSchema(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveApprovalPolicy().Schema, map[string]interface{}{
+		"name": "prod-access",
+		"stage": []interface{}{
+			map[string]interface{}{"approver_groups": []interface{}{"sre"}},
+			map[string]interface{}{"approver_users": []interface{}{"a@example.com", "b@example.com"}, "min_approvals": 2},
+		},
+		"require_justification":    true,
+		"justification_min_length": 20,
+		"max_grant_duration":       "24h",
+		"pending_request_expiry":   "90m",
+	})
+	got := approvalPolicyRequestFromSchema(d)
+	want := &adaptive.ApprovalPolicy{
+		Name: "prod-access",
+		Stages: []adaptive.ApprovalStage{
+			{ApproverUsers: []string{}, ApproverGroups: []string{"sre"}, MinApprovals: 1},
+			{ApproverUsers: []string{"a@example.com", "b@example.com"}, ApproverGroups: []string{}, MinApprovals: 2},
+		},
+		RequireJustification:   true,
+		JustificationMinLength: 20,
+		MaxGrantDuration:       "1d",
+		PendingRequestExpiry:   "90m",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestCheckApprovalPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  adaptive.ApprovalPolicy
+		wantErr string
+	}{
+		{name: "groups only", policy: adaptive.ApprovalPolicy{Stages: []adaptive.ApprovalStage{
+			{ApproverGroups: []string{"sre"}, MinApprovals: 3},
+		}}},
+		{name: "no approvers", policy: adaptive.ApprovalPolicy{Stages: []adaptive.ApprovalStage{
+			{ApproverUsers: []string{"a@example.com"}, MinApprovals: 1},
+			{MinApprovals: 1},
+		}}, wantErr: "stage 2 has no approver_users or approver_groups"},
+		{name: "too few users", policy: adaptive.ApprovalPolicy{Stages: []adaptive.ApprovalStage{
+			{ApproverUsers: []string{"a@example.com"}, MinApprovals: 2},
+		}}, wantErr: "stage 1 needs 2 approvals but has 1 approver_users"},
+		{name: "duplicate user", policy: adaptive.ApprovalPolicy{Stages: []adaptive.ApprovalStage{
+			{ApproverUsers: []string{"a@example.com", "a@example.com"}, MinApprovals: 1},
+		}}, wantErr: `lists "a@example.com" in approver_users more than once`},
+		{name: "min length without justification", policy: adaptive.ApprovalPolicy{
+			Stages:                 []adaptive.ApprovalStage{{ApproverGroups: []string{"sre"}, MinApprovals: 1}},
+			JustificationMinLength: 10,
+		}, wantErr: "requires require_justification"},
+	}
+	for _, tt := range tests {
+		err := checkApprovalPolicy(&tt.policy)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestResourceAdaptiveApprovalPolicyValidation(t *testing.T) {
+	res := ResourceAdaptiveApprovalPolicy()
+	diags := res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":               "p",
+		"stage":              []interface{}{map[string]interface{}{"approver_groups": []interface{}{"sre"}}},
+		"max_grant_duration": "never",
+	}))
+	if !diags.HasError() {
+		t.Error("expected max_grant_duration = never to be rejected")
+	}
+	diags = res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "p",
+	}))
+	if !diags.HasError() {
+		t.Error("expected a policy without stages to be rejected")
+	}
+}
+
+func TestResourceAdaptiveSessionCustomizeDiff_Approval(t *testing.T) {
+	res := ResourceAdaptiveSession()
+	stage := []interface{}{map[string]interface{}{"approver_users": []interface{}{"a@example.com"}, "min_approvals": 2}}
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{name: "shared policy", config: map[string]interface{}{
+			"is_jit_enabled": true, "approval_policy_name": "prod-access",
+		}},
+		{name: "policy without jit", config: map[string]interface{}{
+			"approval_policy_name": "prod-access",
+		}, wantErr: "set is_jit_enabled = true"},
+		{name: "inline policy is checked", config: map[string]interface{}{
+			"is_jit_enabled":  true,
+			"approval_policy": []interface{}{map[string]interface{}{"stage": stage}},
+		}, wantErr: "stage 1 needs 2 approvals"},
+		{name: "inline policy with unknown approvers", config: map[string]interface{}{
+			"is_jit_enabled":  true,
+			"approval_policy": []interface{}{map[string]interface{}{"stage": []interface{}{map[string]interface{}{"approver_users": unknownValue}}}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["name"] = "e"
+			tt.config["resource"] = "r"
+			_, err := res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	diags := res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "e", "resource": "r", "is_jit_enabled": true,
+		"jit_approvers":        []interface{}{"a@example.com"},
+		"approval_policy_name": "prod-access",
+	}))
+	if !diags.HasError() {
+		t.Error("expected jit_approvers and approval_policy_name to conflict")
+	}
+}
+
+// Approvers that come from other resources are unknown at plan; the policy is
+// checked at apply instead of reading them as empty.
+func TestResourceAdaptiveApprovalPolicyCustomizeDiff_UnknownApprovers(t *testing.T) {
+	res := ResourceAdaptiveApprovalPolicy()
+	for name, users := range map[string]interface{}{
+		"list":    unknownValue,
+		"element": []interface{}{unknownValue},
+	} {
+		cfg := map[string]interface{}{"name": "p", "stage": []interface{}{map[string]interface{}{"approver_users": users, "min_approvals": 1}}}
+		if _, err := res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(cfg), nil); err != nil {
+			t.Errorf("unknown %s: %v", name, err)
+		}
+	}
+}
+
+func TestEndpointApprovalFromSchema(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSession().Schema, map[string]interface{}{
+		"name": "e", "resource": "r", "is_jit_enabled": true,
+		"approval_policy": []interface{}{map[string]interface{}{
+			"stage":               []interface{}{map[string]interface{}{"approver_groups": []interface{}{"dba"}}},
+			"allow_self_approval": true,
+		}},
+	})
+	got := endpointApprovalFromSchema(d)
+	if got.ApprovalPolicyName != "" || got.ApprovalPolicy == nil || !got.ApprovalPolicy.AllowSelfApproval ||
+		len(got.ApprovalPolicy.Stages) != 1 || got.ApprovalPolicy.Stages[0].ApproverGroups[0] != "dba" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+// Removing the block must detach the policy, so an empty approval is sent
+// as an explicit empty name and null policy rather than omitted.
+func TestEndpointApprovalFromSchema_Cleared(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSession().Schema, map[string]interface{}{"name": "e", "resource": "r"})
+	body, err := json.Marshal(endpointApprovalFromSchema(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(body); got != `{"approvalPolicyName":"","approvalPolicy":null}` {
+		t.Errorf("cleared approval encoded as %s", got)
+	}
+}
+
+// Read refreshes the approval policy so changes made outside Terraform show
+// as drift, and leaves it alone when the backend does not report it.
+func TestResourceAdaptiveSessionRead_ApprovalPolicy(t *testing.T) {
+	body := `{"Status": "created", "approvalPolicyName": "", "approvalPolicy": {"stages": [{"approverGroups": ["sre"], "minApprovals": 1}], "requireJustification": true}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/read/ep-1") {
+			http.Error(w, "unexpected path "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	client := adaptive.NewClient("test-token", srv.URL)
+
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSession().Schema, map[string]interface{}{
+		"name": "e", "resource": "r", "is_jit_enabled": true, "approval_policy_name": "prod-access",
+	})
+	d.SetId("ep-1")
+	if diags := ResourceAdaptiveSessionRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read returned diagnostics: %+v", diags)
+	}
+	if got := d.Get("approval_policy_name").(string); got != "" {
+		t.Errorf("approval_policy_name = %q, want it cleared", got)
+	}
+	if got := d.Get("approval_policy.0.stage.0.approver_groups.0"); got != "sre" || !d.Get("approval_policy.0.require_justification").(bool) {
+		t.Errorf("approval_policy not refreshed: %+v", d.Get("approval_policy"))
+	}
+
+	body = `{"Status": "created"}`
+	if err := d.Set("approval_policy_name", "prod-access"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := ResourceAdaptiveSessionRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read returned diagnostics: %+v", diags)
+	}
+	if got := d.Get("approval_policy_name").(string); got != "prod-access" {
+		t.Errorf("approval_policy_name = %q, want it kept when not reported", got)
+	}
+}
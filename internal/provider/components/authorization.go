@@ -10,6 +10,7 @@ resource "adaptive_authorization" "example" {
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
@@ -69,6 +70,7 @@ func ResourceAdaptiveAuthorization() *schema.Resource {
 		ReadContext:   ResourceAdaptiveAuthorizationRead,
 		UpdateContext: ResourceAdaptiveAuthorizationUpdate,
 		DeleteContext: ResourceAdaptiveAuthorizationDelete,
+		CustomizeDiff: resourceAdaptiveAuthorizationCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -90,26 +92,66 @@ func ResourceAdaptiveAuthorization() *schema.Resource {
 			},
 			"permissions": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The permission to grant or revoke on the specified resource.",
+				Optional:    true,
+				Computed:    true,
+				Description: "The permission to grant or revoke on the specified resource, in the resource type's raw format. Computed from the typed permission block when one is used instead.",
 			},
+			"postgres_grant":  postgresGrantSchema(),
+			"kubernetes_rule": kubernetesRuleSchema(),
+			"mongodb_role":    mongodbRoleSchema(),
+			"ssh_command":     sshCommandSchema(),
 		},
 	}
 }
 
-func SchemaToAuthorizationConfiguration(d *schema.ResourceData) AuthorizationConfiguration {
+// resourceAdaptiveAuthorizationCustomizeDiff validates the typed permission
+// blocks and plans the rendered document as `permissions`, so the plan shows
+// exactly what will be sent. While a block value is unknown, `permissions`
+// is planned as unknown and the blocks are checked at apply.
+func resourceAdaptiveAuthorizationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !blockValuesKnown(d, "postgres_grant", "kubernetes_rule", "mongodb_role", "ssh_command") {
+		return d.SetNewComputed("permissions")
+	}
+	doc, err := renderAuthorizationPermissions(d)
+	if err != nil {
+		return err
+	}
+	if doc == "" {
+		if raw := d.GetRawConfig(); !raw.IsNull() && raw.GetAttr("permissions").IsNull() {
+			return errors.New("one of `permissions`, `postgres_grant`, `kubernetes_rule`, `mongodb_role` or `ssh_command` must be set")
+		}
+		return nil
+	}
+	if d.Get("permissions").(string) != doc {
+		return d.SetNew("permissions", doc)
+	}
+	return nil
+}
+
+func SchemaToAuthorizationConfiguration(d *schema.ResourceData) (AuthorizationConfiguration, error) {
+	permission := d.Get("permissions").(string)
+	doc, err := renderAuthorizationPermissions(d)
+	if err != nil {
+		return AuthorizationConfiguration{}, err
+	}
+	if doc != "" {
+		permission = doc
+	}
 	return AuthorizationConfiguration{
 		Name:         d.Get("name").(string),
 		Description:  d.Get("description").(string),
 		ResourceType: d.Get("resource_type").(string),
-		Permission:   d.Get("permissions").(string),
-	}
+		Permission:   permission,
+	}, nil
 }
 
 func ResourceAdaptiveAuthorizationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*adaptive.Client)
 
-	obj := SchemaToAuthorizationConfiguration(d)
+	obj, err := SchemaToAuthorizationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	// config, err := json.Marshal(obj)
 	// if err != nil {
 	// 	return diag.FromErr(fmt.Errorf("could not marshal resource configuration %w", err))
@@ -166,9 +208,12 @@ func ResourceAdaptiveAuthorizationUpdate(ctx context.Context, d *schema.Resource
 	//	return diag.Errorf("Cannot change resource of authorization after published")
 	//}
 
-	obj := SchemaToAuthorizationConfiguration(d)
+	obj, err := SchemaToAuthorizationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	_, err := client.UpdateAuthorization(ctx, authID, obj.Name, obj.Description, obj.Permission, obj.ResourceType)
+	_, err = client.UpdateAuthorization(ctx, authID, obj.Name, obj.Description, obj.Permission, obj.ResourceType)
 	if err != nil {
 		return diag.FromErr(err)
 	}
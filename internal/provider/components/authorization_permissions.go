@@ -0,0 +1,360 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v2"
+)
+
+// Typed permission blocks are rendered into the same document a user would
+// otherwise write by hand in `permissions`. Each block is only valid for the
+// resource types whose permission format it produces.
+var permissionBlockResourceTypes = map[string][]string{
+	"postgres_grant":  {"postgres", "postgres_aws_secrets_manager", "yugabytedb", "cockroachdb"},
+	"kubernetes_rule": {"kubernetes"},
+	"mongodb_role":    {"mongodb", "mongodb_atlas", "mongodb_aws_secrets_manager", "mongo36"},
+	"ssh_command":     {"ssh"},
+}
+
+var permissionBlocks = []string{"postgres_grant", "kubernetes_rule", "mongodb_role", "ssh_command"}
+
+var validPostgresPrivileges = []string{
+	"ALL", "SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES",
+	"TRIGGER", "CREATE", "CONNECT", "TEMPORARY", "EXECUTE", "USAGE",
+}
+
+var validKubernetesVerbs = []string{
+	"*", "get", "list", "watch", "create", "update", "patch", "delete",
+	"deletecollection", "impersonate", "bind", "escalate", "use",
+}
+
+func stringListSchema(required bool, description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Required:    required,
+		Optional:    !required,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: description,
+	}
+}
+
+func postgresGrantSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		ConflictsWith: []string{"permissions"},
+		Description:   "A grant on a Postgres-compatible database. Repeatable; rendered into `permissions`. Only valid for postgres, postgres_aws_secrets_manager, yugabytedb and cockroachdb.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"database": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Database the grant applies to.",
+				},
+				"privileges": stringListSchema(true, "Privileges to grant, eg. SELECT, INSERT or ALL."),
+				"objects":    stringListSchema(false, "Tables or other objects the grant covers. Defaults to ALL."),
+			},
+		},
+	}
+}
+
+func kubernetesRuleSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		ConflictsWith: []string{"permissions"},
+		Description:   "An RBAC policy rule. Repeatable; all rules are rendered into a single Role manifest in `permissions`, which applies in every namespace the authorization is used in. Only valid for kubernetes.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"api_groups": stringListSchema(false, "API groups the rule applies to. Use \"\" for the core group. Defaults to the core group."),
+				"resources":  stringListSchema(true, "Resources the rule applies to, eg. pods or deployments."),
+				"verbs":      stringListSchema(true, "Verbs allowed on the resources, eg. get, list, watch."),
+			},
+		},
+	}
+}
+
+func mongodbRoleSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		ConflictsWith: []string{"permissions"},
+		Description:   "A privilege on a MongoDB database or collection. Repeatable; rendered into a role document in `permissions`. Only valid for MongoDB resource types.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"db": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Database the privilege applies to.",
+				},
+				"collection": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "",
+					Description: "Collection the privilege applies to. If not set, it covers every collection in the database.",
+				},
+				"actions": stringListSchema(true, "Actions allowed, eg. find, insert, update."),
+			},
+		},
+	}
+}
+
+func sshCommandSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"permissions"},
+		Description:   "Commands denied on an SSH resource; rendered into `permissions`. Only valid for ssh.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"deny": stringListSchema(true, "Commands users may not run."),
+			},
+		},
+	}
+}
+
+type postgresPermissionDocument struct {
+	Allow []postgresGrant `yaml:"allow"`
+}
+
+type postgresGrant struct {
+	Database   string   `yaml:"database"`
+	Privileges []string `yaml:"privileges"`
+	Objects    []string `yaml:"objects"`
+}
+
+type kubernetesRole struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   kubernetesRoleMetadata `yaml:"metadata"`
+	Rules      []kubernetesPolicyRule `yaml:"rules"`
+}
+
+type kubernetesRoleMetadata struct {
+	Name string `yaml:"name"`
+}
+
+type kubernetesPolicyRule struct {
+	APIGroups []string `yaml:"apiGroups,flow"`
+	Resources []string `yaml:"resources,flow"`
+	Verbs     []string `yaml:"verbs,flow"`
+}
+
+type mongoRoleDocument struct {
+	Role       string           `json:"role"`
+	Privileges []mongoPrivilege `json:"privileges"`
+	Roles      []interface{}    `json:"roles"`
+}
+
+type mongoPrivilege struct {
+	Resource mongoResource `json:"resource"`
+	Actions  []string      `json:"actions"`
+}
+
+type mongoResource struct {
+	DB         string `json:"db"`
+	Collection string `json:"collection"`
+}
+
+// sshPermissionDocument only has a deny list; the SSH permission format has
+// no allow.operations.
+type sshPermissionDocument struct {
+	Deny sshRules `yaml:"deny"`
+}
+
+type sshRules struct {
+	Operations []string `yaml:"operations"`
+}
+
+// permissionBlockGetter abstracts over ResourceData and ResourceDiff so the
+// document can be rendered at plan time and again at apply time.
+type permissionBlockGetter interface {
+	Get(key string) interface{}
+}
+
+// renderAuthorizationPermissions renders the typed permission blocks into a
+// permission document. It returns "" when no typed block is configured, in
+// which case the raw `permissions` string is used as-is.
+func renderAuthorizationPermissions(d permissionBlockGetter) (string, error) {
+	name, _ := d.Get("name").(string)
+	resourceType, _ := d.Get("resource_type").(string)
+
+	used := ""
+	for _, block := range permissionBlocks {
+		if len(blockList(d, block)) == 0 {
+			continue
+		}
+		if used != "" {
+			return "", fmt.Errorf("`%s` and `%s` cannot be combined in one authorization", used, block)
+		}
+		used = block
+	}
+	if used == "" {
+		return "", nil
+	}
+	// An unknown resource_type is rejected by its own validation.
+	if resourceType != "" && !slices.Contains(permissionBlockResourceTypes[used], resourceType) {
+		return "", fmt.Errorf("`%s` cannot be used with resource_type %q; it is only valid for %s", used, resourceType, strings.Join(permissionBlockResourceTypes[used], ", "))
+	}
+
+	blocks := blockList(d, used)
+	switch used {
+	case "postgres_grant":
+		return renderPostgresGrants(blocks)
+	case "kubernetes_rule":
+		return renderKubernetesRules(name, blocks)
+	case "mongodb_role":
+		return renderMongoDBRole(name, blocks)
+	case "ssh_command":
+		return renderSSHCommands(blocks)
+	}
+	return "", nil
+}
+
+func blockList(d permissionBlockGetter, key string) []map[string]interface{} {
+	raw, _ := d.Get(key).([]interface{})
+	out := make([]map[string]interface{}, 0, len(raw))
+	for _, item := range raw {
+		if m, ok := item.(map[string]interface{}); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func blockStrings(m map[string]interface{}, key string) []string {
+	raw, _ := m[key].([]interface{})
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		s, _ := v.(string)
+		out = append(out, s)
+	}
+	return out
+}
+
+func renderPostgresGrants(blocks []map[string]interface{}) (string, error) {
+	doc := postgresPermissionDocument{}
+	for i, b := range blocks {
+		database := b["database"].(string)
+		if database == "" {
+			return "", fmt.Errorf("postgres_grant %d: `database` cannot be empty", i)
+		}
+		privileges := blockStrings(b, "privileges")
+		if len(privileges) == 0 {
+			return "", fmt.Errorf("postgres_grant %d: at least one privilege is required", i)
+		}
+		for j, p := range privileges {
+			p = strings.ToUpper(strings.TrimSpace(p))
+			if !slices.Contains(validPostgresPrivileges, p) {
+				return "", fmt.Errorf("postgres_grant %d: invalid privilege %q; valid privileges are: %s", i, privileges[j], strings.Join(validPostgresPrivileges, ", "))
+			}
+			privileges[j] = p
+		}
+		objects := blockStrings(b, "objects")
+		if len(objects) == 0 {
+			objects = []string{"ALL"}
+		}
+		doc.Allow = append(doc.Allow, postgresGrant{
+			Database:   database,
+			Privileges: privileges,
+			Objects:    objects,
+		})
+	}
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("could not render postgres_grant: %w", err)
+	}
+	return string(out), nil
+}
+
+// renderKubernetesRules emits a single Role holding every rule, since the
+// backend takes one Role manifest per authorization.
+func renderKubernetesRules(name string, blocks []map[string]interface{}) (string, error) {
+	rules := make([]kubernetesPolicyRule, 0, len(blocks))
+	for i, b := range blocks {
+		resources := blockStrings(b, "resources")
+		if len(resources) == 0 {
+			return "", fmt.Errorf("kubernetes_rule %d: at least one resource is required", i)
+		}
+		verbs := blockStrings(b, "verbs")
+		if len(verbs) == 0 {
+			return "", fmt.Errorf("kubernetes_rule %d: at least one verb is required", i)
+		}
+		for _, v := range verbs {
+			if !slices.Contains(validKubernetesVerbs, v) {
+				return "", fmt.Errorf("kubernetes_rule %d: invalid verb %q; valid verbs are: %s", i, v, strings.Join(validKubernetesVerbs, ", "))
+			}
+		}
+		apiGroups := blockStrings(b, "api_groups")
+		if len(apiGroups) == 0 {
+			apiGroups = []string{""}
+		}
+		rules = append(rules, kubernetesPolicyRule{APIGroups: apiGroups, Resources: resources, Verbs: verbs})
+	}
+
+	roleName := name
+	if roleName == "" {
+		roleName = "adaptive-authorization"
+	}
+	out, err := yaml.Marshal(kubernetesRole{
+		APIVersion: "rbac.authorization.k8s.io/v1",
+		Kind:       "Role",
+		Metadata:   kubernetesRoleMetadata{Name: roleName},
+		Rules:      rules,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not render kubernetes_rule: %w", err)
+	}
+	return string(out), nil
+}
+
+func renderMongoDBRole(name string, blocks []map[string]interface{}) (string, error) {
+	doc := mongoRoleDocument{Role: name, Roles: []interface{}{}}
+	for i, b := range blocks {
+		db := b["db"].(string)
+		if db == "" {
+			return "", fmt.Errorf("mongodb_role %d: `db` cannot be empty", i)
+		}
+		actions := blockStrings(b, "actions")
+		if len(actions) == 0 {
+			return "", fmt.Errorf("mongodb_role %d: at least one action is required", i)
+		}
+		for _, a := range actions {
+			if a == "" {
+				return "", fmt.Errorf("mongodb_role %d: actions cannot be empty", i)
+			}
+		}
+		doc.Privileges = append(doc.Privileges, mongoPrivilege{
+			Resource: mongoResource{DB: db, Collection: b["collection"].(string)},
+			Actions:  actions,
+		})
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not render mongodb_role: %w", err)
+	}
+	return string(out), nil
+}
+
+func renderSSHCommands(blocks []map[string]interface{}) (string, error) {
+	deny := blockStrings(blocks[0], "deny")
+	if len(deny) == 0 {
+		return "", fmt.Errorf("ssh_command: at least one command in `deny` is required")
+	}
+	for _, c := range deny {
+		if c == "" {
+			return "", fmt.Errorf("ssh_command: commands cannot be empty")
+		}
+	}
+
+	out, err := yaml.Marshal(sshPermissionDocument{Deny: sshRules{Operations: deny}})
+	if err != nil {
+		return "", fmt.Errorf("could not render ssh_command: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,192 @@
+package components
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestRenderAuthorizationPermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{
+			name: "raw permissions only",
+			raw: map[string]interface{}{
+				"name": "ro", "resource_type": "postgres", "permissions": "SELECT",
+			},
+		},
+		{
+			name: "postgres grant",
+			raw: map[string]interface{}{
+				"name": "ro", "resource_type": "postgres",
+				"postgres_grant": []interface{}{
+					map[string]interface{}{"database": "app", "privileges": []interface{}{"select", "INSERT"}},
+				},
+			},
+			want: "allow:\n- database: app\n  privileges:\n  - SELECT\n  - INSERT\n  objects:\n  - ALL\n",
+		},
+		{
+			name: "postgres grant with bad privilege",
+			raw: map[string]interface{}{
+				"name": "ro", "resource_type": "postgres",
+				"postgres_grant": []interface{}{
+					map[string]interface{}{"database": "app", "privileges": []interface{}{"DROP"}},
+				},
+			},
+			wantErr: `invalid privilege "DROP"`,
+		},
+		{
+			name: "block for the wrong resource type",
+			raw: map[string]interface{}{
+				"name": "ro", "resource_type": "mysql",
+				"postgres_grant": []interface{}{
+					map[string]interface{}{"database": "app", "privileges": []interface{}{"SELECT"}},
+				},
+			},
+			wantErr: "cannot be used with resource_type",
+		},
+		{
+			name: "kubernetes rules in one role",
+			raw: map[string]interface{}{
+				"name": "k8s-dev", "resource_type": "kubernetes",
+				"kubernetes_rule": []interface{}{
+					map[string]interface{}{"resources": []interface{}{"pods"}, "verbs": []interface{}{"get", "list"}},
+					map[string]interface{}{"api_groups": []interface{}{"apps"}, "resources": []interface{}{"deployments"}, "verbs": []interface{}{"patch"}},
+				},
+			},
+			want: "apiVersion: rbac.authorization.k8s.io/v1\nkind: Role\nmetadata:\n  name: k8s-dev\nrules:\n" +
+				"- apiGroups: [\"\"]\n  resources: [pods]\n  verbs: [get, list]\n" +
+				"- apiGroups: [apps]\n  resources: [deployments]\n  verbs: [patch]\n",
+		},
+		{
+			name: "kubernetes rule with bad verb",
+			raw: map[string]interface{}{
+				"name": "k8s-dev", "resource_type": "kubernetes",
+				"kubernetes_rule": []interface{}{
+					map[string]interface{}{"resources": []interface{}{"pods"}, "verbs": []interface{}{"read"}},
+				},
+			},
+			wantErr: `invalid verb "read"`,
+		},
+		{
+			name: "mongodb role",
+			raw: map[string]interface{}{
+				"name": "reader", "resource_type": "mongodb",
+				"mongodb_role": []interface{}{
+					map[string]interface{}{"db": "app", "actions": []interface{}{"find"}},
+				},
+			},
+			want: "{\n  \"role\": \"reader\",\n  \"privileges\": [\n    {\n      \"resource\": {\n        \"db\": \"app\",\n        \"collection\": \"\"\n      },\n      \"actions\": [\n        \"find\"\n      ]\n    }\n  ],\n  \"roles\": []\n}",
+		},
+		{
+			name: "ssh command",
+			raw: map[string]interface{}{
+				"name": "ssh", "resource_type": "ssh",
+				"ssh_command": []interface{}{
+					map[string]interface{}{"deny": []interface{}{"rm", "dd"}},
+				},
+			},
+			want: "deny:\n  operations:\n  - rm\n  - dd\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, ResourceAdaptiveAuthorization().Schema, tt.raw)
+			got, err := renderAuthorizationPermissions(d)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("rendered document mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Attributes the backend permission formats cannot express must fail
+// validation instead of rendering keys the backend ignores.
+func TestAuthorizationPermissionBlocksRejectUnsupported(t *testing.T) {
+	res := ResourceAdaptiveAuthorization()
+	for _, tt := range []struct {
+		name, resourceType, block string
+		attrs                     map[string]interface{}
+		unsupported               string
+	}{
+		{"postgres schema", "postgres", "postgres_grant",
+			map[string]interface{}{"database": "app", "privileges": []interface{}{"SELECT"}}, "schema"},
+		{"kubernetes namespaces", "kubernetes", "kubernetes_rule",
+			map[string]interface{}{"resources": []interface{}{"pods"}, "verbs": []interface{}{"get"}}, "namespaces"},
+		{"ssh allow", "ssh", "ssh_command",
+			map[string]interface{}{"deny": []interface{}{"rm"}}, "allow"},
+	} {
+		raw := map[string]interface{}{"name": "a", "resource_type": tt.resourceType, tt.block: []interface{}{tt.attrs}}
+		if diags := res.Validate(terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+			t.Fatalf("%s: supported block rejected: %+v", tt.name, diags)
+		}
+		tt.attrs[tt.unsupported] = []interface{}{"x"}
+		if diags := res.Validate(terraform.NewResourceConfigRaw(raw)); !diags.HasError() {
+			t.Errorf("%s: expected %s to be rejected", tt.name, tt.unsupported)
+		}
+	}
+}
+
+// unknownValue is how the SDK shims a value that is unknown until apply
+// (hcl2shim.UnknownVariableValue, which is internal).
+const unknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// A block value that comes from another resource is unknown at plan, so the
+// blocks cannot be checked or rendered yet; permissions must be planned as
+// unknown instead of failing the plan.
+func TestResourceAdaptiveAuthorizationCustomizeDiff_Unknown(t *testing.T) {
+	res := ResourceAdaptiveAuthorization()
+	for name, grant := range map[string]map[string]interface{}{
+		"database":          {"database": unknownValue, "privileges": []interface{}{"SELECT"}},
+		"privileges":        {"database": "app", "privileges": unknownValue},
+		"privilege element": {"database": "app", "privileges": []interface{}{unknownValue}},
+	} {
+		cfg := map[string]interface{}{"name": "a", "resource_type": "postgres", "postgres_grant": []interface{}{grant}}
+		diff, err := res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(cfg), nil)
+		if err != nil {
+			t.Errorf("unknown %s: %v", name, err)
+			continue
+		}
+		if attr := diff.Attributes["permissions"]; attr == nil || !attr.NewComputed {
+			t.Errorf("unknown %s: permissions not planned as unknown: %+v", name, attr)
+		}
+	}
+}
+
+func TestRawConfigValue(t *testing.T) {
+	raw := cty.ObjectVal(map[string]cty.Value{
+		"stage": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"approver_users": cty.ListVal([]cty.Value{cty.StringVal("a@example.com"), cty.UnknownVal(cty.String)}),
+			"min_approvals":  cty.NumberIntVal(1),
+		})}),
+	})
+	if !rawConfigValue(raw, "stage.0.min_approvals").IsWhollyKnown() {
+		t.Error("known attribute reported unknown")
+	}
+	for _, key := range []string{"stage", "stage.0.approver_users", "stage.0.approver_users.1"} {
+		if rawConfigValue(raw, key).IsWhollyKnown() {
+			t.Errorf("%s reported wholly known", key)
+		}
+	}
+	if !rawConfigValue(raw, "stage.3.approver_users").IsNull() {
+		t.Error("missing element not null")
+	}
+}
@@ -0,0 +1,297 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/adaptive-scale/terraform-provider-adaptive/internal/provider/integrations"
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Default plan-time maximums for endpoint pod resources, matching the largest
+// values the provider used to accept. They are not the workspace's limits,
+// which the provider cannot read; the provider block can change them.
+const (
+	DefaultEndpointMaxCPU    = "8"
+	DefaultEndpointMaxMemory = "8Gi"
+)
+
+var quantityPattern = regexp.MustCompile(`^\+?([0-9]+(?:\.[0-9]*)?|\.[0-9]+)(Ki|Mi|Gi|Ti|Pi|Ei|n|u|m|k|M|G|T|P|E|[eE][+-]?[0-9]+)?$`)
+
+var quantityBinarySuffixes = map[string]int64{
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+var quantityDecimalExponents = map[string]int64{
+	"n": -9, "u": -6, "m": -3, "": 0, "k": 3, "M": 6, "G": 9, "T": 12, "P": 15, "E": 18,
+}
+
+// parseQuantity parses a Kubernetes quantity such as "500m", "2", "1.5Gi" or
+// "6e9" into its exact value. Negative quantities are rejected; endpoint
+// resources are always positive.
+func parseQuantity(s string) (*big.Rat, error) {
+	m := quantityPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a quantity such as 500m, 2, 512Mi or 6Gi", s)
+	}
+	v, ok := new(big.Rat).SetString(m[1])
+	if !ok {
+		return nil, fmt.Errorf("%q is not a quantity such as 500m, 2, 512Mi or 6Gi", s)
+	}
+	suffix := m[2]
+	if mult, ok := quantityBinarySuffixes[suffix]; ok {
+		return v.Mul(v, new(big.Rat).SetInt64(mult)), nil
+	}
+	exp, ok := quantityDecimalExponents[suffix]
+	if !ok {
+		var err error
+		if exp, err = strconv.ParseInt(suffix[1:], 10, 64); err != nil || exp < -18 || exp > 18 {
+			return nil, fmt.Errorf("%q has an exponent out of range", s)
+		}
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(exp)), nil))
+	if exp < 0 {
+		return v.Quo(v, scale), nil
+	}
+	return v.Mul(v, scale), nil
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// validateQuantity accepts Kubernetes quantity strings, and the empty string
+// for "not set".
+func validateQuantity(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s must be a string", k)}
+	}
+	if v == "" {
+		return nil, nil
+	}
+	q, err := parseQuantity(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	if q.Sign() == 0 {
+		return nil, []error{fmt.Errorf("%s must be greater than zero", k)}
+	}
+	return nil, nil
+}
+
+// ValidateQuantity is validateQuantity for the provider's maximums.
+var ValidateQuantity schema.SchemaValidateFunc = validateQuantity
+
+// suppressEquivalentQuantity ignores differences between spellings of the
+// same quantity, such as "1" and "1000m", or "1Gi" and "1024Mi".
+func suppressEquivalentQuantity(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return old == new
+	}
+	o, err := parseQuantity(old)
+	if err != nil {
+		return false
+	}
+	n, err := parseQuantity(new)
+	if err != nil {
+		return false
+	}
+	return o.Cmp(n) == 0
+}
+
+// endpointResourceNames are the attributes of the requests and limits blocks.
+var endpointResourceNames = []string{"cpu", "memory", "ephemeral_storage"}
+
+func endpointResourceBlockSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"cpu", "memory"},
+		Description:   description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cpu": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ValidateFunc:     validateQuantity,
+					DiffSuppressFunc: suppressEquivalentQuantity,
+					Description:      "CPU as a Kubernetes quantity, such as 500m or 3.",
+				},
+				"memory": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ValidateFunc:     validateQuantity,
+					DiffSuppressFunc: suppressEquivalentQuantity,
+					Description:      "Memory as a Kubernetes quantity, such as 512Mi or 6Gi.",
+				},
+				"ephemeral_storage": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ValidateFunc:     validateQuantity,
+					DiffSuppressFunc: suppressEquivalentQuantity,
+					Description:      "Ephemeral storage as a Kubernetes quantity, such as 10Gi.",
+				},
+			},
+		},
+	}
+}
+
+// endpointResourceListFromSchema reads a requests or limits block. It
+// returns nil when the block is absent or empty.
+func endpointResourceListFromSchema(raw interface{}) *adaptive.EndpointResourceList {
+	list, _ := raw.([]interface{})
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	block := list[0].(map[string]interface{})
+	l := &adaptive.EndpointResourceList{
+		CPU:              block["cpu"].(string),
+		Memory:           block["memory"].(string),
+		EphemeralStorage: block["ephemeral_storage"].(string),
+	}
+	if *l == (adaptive.EndpointResourceList{}) {
+		return nil
+	}
+	return l
+}
+
+// endpointResourceValues are the values of one requests or limits block, or
+// of the top-level cpu and memory when block is empty.
+type endpointResourceValues struct {
+	block string
+	list  *adaptive.EndpointResourceList
+}
+
+func (e *endpointResourceValues) get(name string) string {
+	if e.list == nil {
+		return ""
+	}
+	switch name {
+	case "cpu":
+		return e.list.CPU
+	case "memory":
+		return e.list.Memory
+	}
+	return e.list.EphemeralStorage
+}
+
+// endpointResourcesFromSchema returns the requests and limits to send, and
+// the single cpu and memory values for backends that only read those: the
+// top-level attributes, or else the limit, or else the request.
+func endpointResourcesFromSchema(d integrations.SchemaGetter) (resources *adaptive.EndpointResources, cpu, memory string) {
+	requests := endpointResourceListFromSchema(d.Get("requests"))
+	limits := endpointResourceListFromSchema(d.Get("limits"))
+	cpu, _ = d.Get("cpu").(string)
+	memory, _ = d.Get("memory").(string)
+	if requests == nil && limits == nil {
+		return nil, cpu, memory
+	}
+	for _, l := range []*adaptive.EndpointResourceList{requests, limits} {
+		if l == nil {
+			continue
+		}
+		if l.CPU != "" {
+			cpu = l.CPU
+		}
+		if l.Memory != "" {
+			memory = l.Memory
+		}
+	}
+	return &adaptive.EndpointResources{Requests: requests, Limits: limits}, cpu, memory
+}
+
+// endpointResourceMaximums returns the configured maximum for each resource
+// name. A nil maximum means unbounded.
+func endpointResourceMaximums(opts adaptive.ProviderOptions) (map[string]*big.Rat, error) {
+	max := map[string]*big.Rat{}
+	for name, v := range map[string]string{
+		"cpu":               opts.EndpointMaxCPU,
+		"memory":            opts.EndpointMaxMemory,
+		"ephemeral_storage": opts.EndpointMaxEphemeralStorage,
+	} {
+		if v == "" {
+			continue
+		}
+		q, err := parseQuantity(v)
+		if err != nil {
+			return nil, fmt.Errorf("provider endpoint maximum for %s: %w", name, err)
+		}
+		max[name] = q
+	}
+	return max, nil
+}
+
+// checkEndpointResources checks each value is within its maximum, CPU is at
+// least 1m, and no request exceeds its limit.
+func checkEndpointResources(values []endpointResourceValues, maximums map[string]*big.Rat, maxNames map[string]string) error {
+	var errs []error
+	parsed := map[string]map[string]*big.Rat{}
+	for _, v := range values {
+		parsed[v.block] = map[string]*big.Rat{}
+		for _, name := range endpointResourceNames {
+			s := v.get(name)
+			if s == "" {
+				continue
+			}
+			q, err := parseQuantity(s)
+			if err != nil {
+				// Reported by the attribute's ValidateFunc.
+				continue
+			}
+			parsed[v.block][name] = q
+			attr := v.block + "." + name
+			if v.block == "" {
+				attr = name
+			}
+			if name == "cpu" && q.Cmp(big.NewRat(1, 1000)) < 0 {
+				errs = append(errs, fmt.Errorf("%s %q is below 1m, the smallest CPU quantity", attr, s))
+			}
+			if m := maximums[name]; m != nil && q.Cmp(m) > 0 {
+				errs = append(errs, fmt.Errorf("%s %q exceeds the workspace maximum of %s; raise endpoint_max_%s in the provider block if this is intended", attr, s, maxNames[name], name))
+			}
+		}
+	}
+	for _, name := range endpointResourceNames {
+		req, lim := parsed["requests"][name], parsed["limits"][name]
+		if req != nil && lim != nil && req.Cmp(lim) > 0 {
+			errs = append(errs, fmt.Errorf("requests.%s must not exceed limits.%s", name, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// customizeDiffEndpointResources enforces checkEndpointResources at plan
+// time, once every resource attribute is known.
+func customizeDiffEndpointResources(d *schema.ResourceDiff, m interface{}) error {
+	if !newValuesKnown(d, "cpu", "memory", "requests", "limits") {
+		return nil
+	}
+	opts := adaptive.ProviderOptions{EndpointMaxCPU: DefaultEndpointMaxCPU, EndpointMaxMemory: DefaultEndpointMaxMemory}
+	if client, ok := m.(*adaptive.Client); ok && client != nil {
+		opts = client.Options
+	}
+	maximums, err := endpointResourceMaximums(opts)
+	if err != nil {
+		return err
+	}
+	maxNames := map[string]string{"cpu": opts.EndpointMaxCPU, "memory": opts.EndpointMaxMemory, "ephemeral_storage": opts.EndpointMaxEphemeralStorage}
+
+	cpu, _ := d.Get("cpu").(string)
+	memory, _ := d.Get("memory").(string)
+	values := []endpointResourceValues{
+		{block: "", list: &adaptive.EndpointResourceList{CPU: cpu, Memory: memory}},
+		{block: "requests", list: endpointResourceListFromSchema(d.Get("requests"))},
+		{block: "limits", list: endpointResourceListFromSchema(d.Get("limits"))},
+	}
+	return checkEndpointResources(values, maximums, maxNames)
+}
@@ -0,0 +1,142 @@
+package components
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseQuantity(t *testing.T) {
+	for in, want := range map[string]*big.Rat{
+		"3":     big.NewRat(3, 1),
+		"500m":  big.NewRat(1, 2),
+		"0.5":   big.NewRat(1, 2),
+		".25":   big.NewRat(1, 4),
+		"6Gi":   big.NewRat(6<<30, 1),
+		"1.5Ki": big.NewRat(1536, 1),
+		"2k":    big.NewRat(2000, 1),
+		"6e9":   big.NewRat(6000000000, 1),
+		"100n":  big.NewRat(1, 10000000),
+	} {
+		got, err := parseQuantity(in)
+		if err != nil {
+			t.Errorf("%q: %v", in, err)
+			continue
+		}
+		if got.Cmp(want) != 0 {
+			t.Errorf("%q = %s, want %s", in, got.RatString(), want.RatString())
+		}
+	}
+	for _, in := range []string{"", "-1", "1.5 Gi", "6GB", "Gi", "1e99", "0x10"} {
+		if _, err := parseQuantity(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestSuppressEquivalentQuantity(t *testing.T) {
+	for _, tt := range []struct {
+		old, new string
+		want     bool
+	}{
+		{"1", "1000m", true},
+		{"1Gi", "1024Mi", true},
+		{"0.5", "500m", true},
+		{"1G", "1Gi", false},
+		{"", "1", false},
+	} {
+		if got := suppressEquivalentQuantity("cpu", tt.old, tt.new, nil); got != tt.want {
+			t.Errorf("suppress(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointResourcesFromSchema(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSession().Schema, map[string]interface{}{
+		"name":     "pg-dump",
+		"resource": "db",
+		"requests": []interface{}{map[string]interface{}{"cpu": "1", "memory": "2Gi"}},
+		"limits":   []interface{}{map[string]interface{}{"cpu": "3", "memory": "6Gi", "ephemeral_storage": "20Gi"}},
+	})
+	resources, cpu, memory := endpointResourcesFromSchema(d)
+	if resources == nil || resources.Requests.CPU != "1" || resources.Limits.EphemeralStorage != "20Gi" {
+		t.Fatalf("resources = %+v", resources)
+	}
+	if cpu != "3" || memory != "6Gi" {
+		t.Errorf("single values = %q, %q, want the limits", cpu, memory)
+	}
+
+	d = schema.TestResourceDataRaw(t, ResourceAdaptiveSession().Schema, map[string]interface{}{
+		"name": "small", "resource": "db", "cpu": "250m", "memory": "512Mi",
+	})
+	if resources, cpu, memory := endpointResourcesFromSchema(d); resources != nil || cpu != "250m" || memory != "512Mi" {
+		t.Errorf("got %+v, %q, %q", resources, cpu, memory)
+	}
+}
+
+func TestResourceAdaptiveSessionCustomizeDiff_Resources(t *testing.T) {
+	res := ResourceAdaptiveSession()
+	raised := adaptive.NewClient("test-token", "http://127.0.0.1:0")
+	raised.Options = adaptive.ProviderOptions{EndpointMaxCPU: "16", EndpointMaxMemory: "64Gi", EndpointMaxEphemeralStorage: "10Gi"}
+
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		meta    interface{}
+		wantErr string
+	}{
+		{name: "within defaults", config: map[string]interface{}{
+			"limits": []interface{}{map[string]interface{}{"cpu": "3", "memory": "6Gi"}},
+		}},
+		{name: "over the default cpu maximum", config: map[string]interface{}{
+			"cpu": "12",
+		}, wantErr: `cpu "12" exceeds the workspace maximum of 8`},
+		{name: "raised maximum", meta: raised, config: map[string]interface{}{
+			"limits": []interface{}{map[string]interface{}{"cpu": "12", "memory": "32Gi"}},
+		}},
+		{name: "ephemeral storage maximum", meta: raised, config: map[string]interface{}{
+			"limits": []interface{}{map[string]interface{}{"ephemeral_storage": "20Gi"}},
+		}, wantErr: "limits.ephemeral_storage"},
+		{name: "request above limit", config: map[string]interface{}{
+			"requests": []interface{}{map[string]interface{}{"memory": "4Gi"}},
+			"limits":   []interface{}{map[string]interface{}{"memory": "2Gi"}},
+		}, wantErr: "requests.memory must not exceed limits.memory"},
+		{name: "cpu below 1m", config: map[string]interface{}{
+			"requests": []interface{}{map[string]interface{}{"cpu": "100u"}},
+		}, wantErr: "below 1m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["name"] = "e"
+			tt.config["resource"] = "r"
+			meta := tt.meta
+			if meta != nil {
+				// Skip the resource lookups of the consistency check.
+				tt.config["resource"] = ""
+			}
+			_, err := res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), meta)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	diags := res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "e", "resource": "r", "cpu": "2",
+		"limits": []interface{}{map[string]interface{}{"cpu": "3"}},
+	}))
+	if !diags.HasError() {
+		t.Error("expected cpu and limits to conflict")
+	}
+}
@@ -0,0 +1,53 @@
+package components
+
+import (
+	"fmt"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/exp/slices"
+)
+
+// deletionProtectionSchema is shared by every resource that can be protected
+// from destroy. It has no default so adding it does not diff existing state.
+func deletionProtectionSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: fmt.Sprintf("When true, Terraform refuses to destroy or replace this %s. Set it to false and apply before removing the %s.", kind, kind),
+	}
+}
+
+// checkDeletionProtection returns an error diagnostic when d is protected,
+// either by its own deletion_protection flag or by carrying one of the
+// provider's protect_tags. It reads state, so disabling protection needs an
+// apply of its own before the destroy.
+func checkDeletionProtection(d *schema.ResourceData, client *adaptive.Client, kind string) diag.Diagnostics {
+	name := d.Get("name").(string)
+	if d.Get("deletion_protection").(bool) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Cannot destroy %s %q: deletion protection is enabled", kind, name),
+			Detail:   fmt.Sprintf("Set `deletion_protection = false` on the %s and apply before destroying or replacing it.", kind),
+		}}
+	}
+
+	if client == nil || len(client.Options.ProtectTags) == 0 {
+		return nil
+	}
+	tags, ok := d.Get("tags").([]interface{})
+	if !ok {
+		return nil
+	}
+	for _, t := range tags {
+		if tag, ok := t.(string); ok && slices.Contains(client.Options.ProtectTags, tag) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Cannot destroy %s %q: tag %q is protected", kind, name, tag),
+				Detail:   fmt.Sprintf("The provider's `protect_tags` includes %q. Remove the tag from the %s, or from `protect_tags`, and apply before destroying it.", tag, kind),
+			}}
+		}
+	}
+	return nil
+}
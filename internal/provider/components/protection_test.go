@@ -0,0 +1,89 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckDeletionProtection(t *testing.T) {
+	protected := adaptive.NewClient("test-token", "http://localhost")
+	protected.Options.ProtectTags = []string{"prod"}
+
+	tests := []struct {
+		name    string
+		res     *schema.Resource
+		kind    string
+		raw     map[string]interface{}
+		client  *adaptive.Client
+		wantErr string
+	}{
+		{
+			name:    "resource flag set",
+			res:     ResourceAdaptiveResource(),
+			kind:    "resource",
+			raw:     map[string]interface{}{"name": "db", "type": "postgres", "deletion_protection": true},
+			client:  adaptive.NewClient("test-token", "http://localhost"),
+			wantErr: `Cannot destroy resource "db": deletion protection is enabled`,
+		},
+		{
+			name:    "endpoint flag set",
+			res:     ResourceAdaptiveSession(),
+			kind:    "endpoint",
+			raw:     map[string]interface{}{"name": "ep", "resource": "db", "deletion_protection": true},
+			client:  adaptive.NewClient("test-token", "http://localhost"),
+			wantErr: `Cannot destroy endpoint "ep": deletion protection is enabled`,
+		},
+		{
+			name:    "group flag set",
+			res:     ResourceAdaptiveTeam(),
+			kind:    "group",
+			raw:     map[string]interface{}{"name": "devs", "deletion_protection": true},
+			client:  adaptive.NewClient("test-token", "http://localhost"),
+			wantErr: `Cannot destroy group "devs": deletion protection is enabled`,
+		},
+		{
+			name:    "protected tag",
+			res:     ResourceAdaptiveResource(),
+			kind:    "resource",
+			raw:     map[string]interface{}{"name": "db", "type": "postgres", "tags": []interface{}{"team-a", "prod"}},
+			client:  protected,
+			wantErr: `Cannot destroy resource "db": tag "prod" is protected`,
+		},
+		{
+			name:   "unprotected tag",
+			res:    ResourceAdaptiveResource(),
+			kind:   "resource",
+			raw:    map[string]interface{}{"name": "db", "type": "postgres", "tags": []interface{}{"staging"}},
+			client: protected,
+		},
+		{
+			name:   "group without tags",
+			res:    ResourceAdaptiveTeam(),
+			kind:   "group",
+			raw:    map[string]interface{}{"name": "devs"},
+			client: protected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, tt.res.Schema, tt.raw)
+			diags := checkDeletionProtection(d, tt.client, tt.kind)
+			if tt.wantErr == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %+v", diags)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatal("expected deletion to be refused")
+			}
+			if !strings.Contains(diags[0].Summary, tt.wantErr) {
+				t.Fatalf("expected summary to contain %q, got %q", tt.wantErr, diags[0].Summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package components
+
+import (
+	"context"
+
+	"github.com/adaptive-scale/terraform-provider-adaptive/internal/provider/integrations"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceAdaptiveResourceV0 is the adaptive_resource schema before SSH
+// resources gained auth_method, key_passphrase and ssh_certificate. It is
+// frozen: only the attribute types matter to the upgrade, and they must not
+// follow later changes to the live schema.
+func resourceAdaptiveResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"access_key_id":        {Type: schema.TypeString, Optional: true},
+			"adopt_existing":       {Type: schema.TypeBool, Optional: true},
+			"annotations":          {Type: schema.TypeString, Optional: true},
+			"api_client_id":        {Type: schema.TypeString, Optional: true},
+			"api_client_secret":    {Type: schema.TypeString, Optional: true},
+			"api_key":              {Type: schema.TypeString, Optional: true},
+			"api_server":           {Type: schema.TypeString, Optional: true},
+			"api_token":            {Type: schema.TypeString, Optional: true},
+			"app_id":               {Type: schema.TypeString, Optional: true},
+			"app_key":              {Type: schema.TypeString, Optional: true},
+			"application_id":       {Type: schema.TypeString, Optional: true},
+			"application_name":     {Type: schema.TypeString, Optional: true},
+			"arn":                  {Type: schema.TypeString, Optional: true},
+			"aws_arn":              {Type: schema.TypeString, Optional: true},
+			"aws_region_name":      {Type: schema.TypeString, Optional: true},
+			"client_id":            {Type: schema.TypeString, Optional: true},
+			"client_secret":        {Type: schema.TypeString, Optional: true},
+			"clientcert":           {Type: schema.TypeString, Optional: true},
+			"cluster_cert":         {Type: schema.TypeString, Optional: true},
+			"cluster_token":        {Type: schema.TypeString, Optional: true},
+			"create_if_not_exists": {Type: schema.TypeBool, Optional: true},
+			"database_account":     {Type: schema.TypeString, Optional: true},
+			"database_name":        {Type: schema.TypeString, Optional: true},
+			"database_password":    {Type: schema.TypeString, Optional: true},
+			"database_username":    {Type: schema.TypeString, Optional: true},
+			"dd_api_key":           {Type: schema.TypeString, Optional: true},
+			"dd_site":              {Type: schema.TypeString, Optional: true},
+			"default_cluster":      {Type: schema.TypeString, Optional: true},
+			"default_user":         {Type: schema.TypeString, Optional: true},
+			"deletion_protection":  {Type: schema.TypeBool, Optional: true},
+			"domain":               {Type: schema.TypeString, Optional: true},
+			"host":                 {Type: schema.TypeString, Optional: true},
+			"hostname":             {Type: schema.TypeString, Optional: true},
+			"hosts":                {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"image":                {Type: schema.TypeString, Optional: true},
+			"index":                {Type: schema.TypeString, Optional: true},
+			"key":                  {Type: schema.TypeString, Optional: true},
+			"key_file":             {Type: schema.TypeString, Optional: true},
+			"login_url":            {Type: schema.TypeString, Optional: true},
+			"manage_targets":       {Type: schema.TypeBool, Optional: true},
+			"name":                 {Type: schema.TypeString, Required: true},
+			"namespace":            {Type: schema.TypeString, Optional: true},
+			"network_id":           {Type: schema.TypeString, Optional: true},
+			"node_affinity":        {Type: schema.TypeString, Optional: true},
+			"node_selector":        {Type: schema.TypeString, Optional: true},
+			"organization_id":      {Type: schema.TypeString, Optional: true},
+			"password":             {Type: schema.TypeString, Optional: true},
+			"port":                 {Type: schema.TypeString, Optional: true},
+			"private_key":          {Type: schema.TypeString, Optional: true},
+			"project_id":           {Type: schema.TypeString, Optional: true},
+			"protocol":             {Type: schema.TypeString, Optional: true},
+			"public_key":           {Type: schema.TypeString, Optional: true},
+			"region":               {Type: schema.TypeString, Optional: true},
+			"region_name":          {Type: schema.TypeString, Optional: true},
+			"role":                 {Type: schema.TypeString, Optional: true},
+			"root_cert":            {Type: schema.TypeString, Optional: true},
+			"schema":               {Type: schema.TypeString, Optional: true},
+			"secret_access_key":    {Type: schema.TypeString, Optional: true},
+			"secret_id":            {Type: schema.TypeString, Optional: true},
+			"server": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"address":  {Type: schema.TypeString, Required: true},
+				"key":      {Type: schema.TypeString, Optional: true},
+				"labels":   {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				"password": {Type: schema.TypeString, Optional: true},
+				"port":     {Type: schema.TypeInt, Optional: true},
+				"username": {Type: schema.TypeString, Optional: true},
+			}}},
+			"service_account_name": {Type: schema.TypeString, Optional: true},
+			"shared_secret":        {Type: schema.TypeString, Optional: true},
+			"ssl_mode":             {Type: schema.TypeString, Optional: true},
+			"sub_system_name":      {Type: schema.TypeString, Optional: true},
+			"tags":                 {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"targets": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"domain":   {Type: schema.TypeString, Optional: true},
+				"host":     {Type: schema.TypeString, Required: true},
+				"id":       {Type: schema.TypeString, Required: true},
+				"name":     {Type: schema.TypeString, Optional: true},
+				"password": {Type: schema.TypeString, Required: true},
+				"port":     {Type: schema.TypeInt, Optional: true},
+				"record":   {Type: schema.TypeBool, Optional: true},
+				"username": {Type: schema.TypeString, Required: true},
+			}}},
+			"tenant_id":           {Type: schema.TypeString, Optional: true},
+			"tls_cert_file":       {Type: schema.TypeString, Optional: true},
+			"tls_key_file":        {Type: schema.TypeString, Optional: true},
+			"tls_root_cert":       {Type: schema.TypeString, Optional: true},
+			"token_id":            {Type: schema.TypeString, Optional: true},
+			"tolerations":         {Type: schema.TypeString, Optional: true},
+			"type":                {Type: schema.TypeString, Required: true},
+			"uri":                 {Type: schema.TypeString, Optional: true},
+			"url":                 {Type: schema.TypeString, Optional: true},
+			"urls":                {Type: schema.TypeString, Optional: true},
+			"use_proxy":           {Type: schema.TypeBool, Optional: true},
+			"use_service_account": {Type: schema.TypeBool, Optional: true},
+			"use_tenant":          {Type: schema.TypeBool, Optional: true},
+			"username":            {Type: schema.TypeString, Optional: true},
+			"version":             {Type: schema.TypeString, Optional: true},
+			"warehouse":           {Type: schema.TypeString, Optional: true},
+			"webui_port":          {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+// resourceAdaptiveResourceStateUpgradeV0 records the auth method existing SSH
+// resources were implicitly using: key auth when a key was set, password
+// otherwise.
+func resourceAdaptiveResourceStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	if rawState["type"] != "ssh" {
+		return rawState, nil
+	}
+	if method, _ := rawState["auth_method"].(string); method != "" {
+		return rawState, nil
+	}
+
+	key, _ := rawState["key"].(string)
+	if key != "" {
+		rawState["auth_method"] = "private_key"
+	} else {
+		rawState["auth_method"] = "password"
+	}
+	return rawState, nil
+}
+
+// resourceAdaptiveResourceV1 is the adaptive_resource schema before `host`,
+// `region`, `arn` and `password` became the canonical names of their
+// deprecated aliases. Like resourceAdaptiveResourceV0 it is frozen.
+func resourceAdaptiveResourceV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"access_key_id":            {Type: schema.TypeString, Optional: true},
+			"adopt_existing":           {Type: schema.TypeBool, Optional: true},
+			"annotations":              {Type: schema.TypeString, Optional: true},
+			"api_client_id":            {Type: schema.TypeString, Optional: true},
+			"api_client_secret":        {Type: schema.TypeString, Optional: true},
+			"api_key":                  {Type: schema.TypeString, Optional: true},
+			"api_server":               {Type: schema.TypeString, Optional: true},
+			"api_token":                {Type: schema.TypeString, Optional: true},
+			"app_id":                   {Type: schema.TypeString, Optional: true},
+			"app_key":                  {Type: schema.TypeString, Optional: true},
+			"application_id":           {Type: schema.TypeString, Optional: true},
+			"application_name":         {Type: schema.TypeString, Optional: true},
+			"arn":                      {Type: schema.TypeString, Optional: true},
+			"auth_method":              {Type: schema.TypeString, Optional: true, Computed: true},
+			"aws_arn":                  {Type: schema.TypeString, Optional: true},
+			"aws_region_name":          {Type: schema.TypeString, Optional: true},
+			"client_certificate":       {Type: schema.TypeString, Optional: true},
+			"client_id":                {Type: schema.TypeString, Optional: true},
+			"client_key":               {Type: schema.TypeString, Optional: true},
+			"client_secret":            {Type: schema.TypeString, Optional: true},
+			"clientcert":               {Type: schema.TypeString, Optional: true},
+			"clientcert_fingerprint":   {Type: schema.TypeString, Computed: true},
+			"clientcert_not_after":     {Type: schema.TypeString, Computed: true},
+			"cluster_cert":             {Type: schema.TypeString, Optional: true},
+			"cluster_cert_fingerprint": {Type: schema.TypeString, Computed: true},
+			"cluster_cert_not_after":   {Type: schema.TypeString, Computed: true},
+			"cluster_token":            {Type: schema.TypeString, Optional: true},
+			"connection_string":        {Type: schema.TypeString, Optional: true},
+			"create_if_not_exists":     {Type: schema.TypeBool, Optional: true},
+			"database_account":         {Type: schema.TypeString, Optional: true},
+			"database_name":            {Type: schema.TypeString, Optional: true},
+			"database_password":        {Type: schema.TypeString, Optional: true},
+			"database_username":        {Type: schema.TypeString, Optional: true},
+			"dd_api_key":               {Type: schema.TypeString, Optional: true},
+			"dd_site":                  {Type: schema.TypeString, Optional: true},
+			"default_cluster":          {Type: schema.TypeString, Optional: true},
+			"default_user":             {Type: schema.TypeString, Optional: true},
+			"deletion_protection":      {Type: schema.TypeBool, Optional: true},
+			"domain":                   {Type: schema.TypeString, Optional: true},
+			"host":                     {Type: schema.TypeString, Optional: true},
+			"hostname":                 {Type: schema.TypeString, Optional: true},
+			"hosts":                    {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"image":                    {Type: schema.TypeString, Optional: true},
+			"impersonate_groups":       {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"impersonate_user":         {Type: schema.TypeString, Optional: true},
+			"index":                    {Type: schema.TypeString, Optional: true},
+			"jump_host": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"host":            {Type: schema.TypeString, Required: true},
+				"key":             {Type: schema.TypeString, Required: true},
+				"known_host_keys": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				"port":            {Type: schema.TypeInt, Optional: true},
+				"username":        {Type: schema.TypeString, Required: true},
+			}}},
+			"key":                {Type: schema.TypeString, Optional: true},
+			"key_file":           {Type: schema.TypeString, Optional: true},
+			"key_passphrase":     {Type: schema.TypeString, Optional: true},
+			"known_host_keys":    {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"kubeconfig":         {Type: schema.TypeString, Optional: true},
+			"kubeconfig_context": {Type: schema.TypeString, Optional: true},
+			"login_url":          {Type: schema.TypeString, Optional: true},
+			"manage_targets":     {Type: schema.TypeBool, Optional: true},
+			"name":               {Type: schema.TypeString, Required: true},
+			"namespace":          {Type: schema.TypeString, Optional: true},
+			"namespaces":         {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"network_id":         {Type: schema.TypeString, Optional: true},
+			"node_affinity":      {Type: schema.TypeString, Optional: true},
+			"node_affinity_term": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"match_expression": {Type: schema.TypeList, Required: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+					"key":      {Type: schema.TypeString, Required: true},
+					"operator": {Type: schema.TypeString, Required: true},
+					"values":   {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				}}},
+				"weight": {Type: schema.TypeInt, Optional: true},
+			}}},
+			"node_selector":         {Type: schema.TypeString, Optional: true},
+			"organization_id":       {Type: schema.TypeString, Optional: true},
+			"password":              {Type: schema.TypeString, Optional: true},
+			"pod_annotations":       {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"pod_node_selector":     {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"port":                  {Type: schema.TypeString, Optional: true},
+			"private_key":           {Type: schema.TypeString, Optional: true},
+			"project_id":            {Type: schema.TypeString, Optional: true},
+			"protocol":              {Type: schema.TypeString, Optional: true},
+			"public_key":            {Type: schema.TypeString, Optional: true},
+			"region":                {Type: schema.TypeString, Optional: true},
+			"region_name":           {Type: schema.TypeString, Optional: true},
+			"role":                  {Type: schema.TypeString, Optional: true},
+			"root_cert":             {Type: schema.TypeString, Optional: true},
+			"root_cert_fingerprint": {Type: schema.TypeString, Computed: true},
+			"root_cert_not_after":   {Type: schema.TypeString, Computed: true},
+			"schema":                {Type: schema.TypeString, Optional: true},
+			"secret_access_key":     {Type: schema.TypeString, Optional: true},
+			"secret_id":             {Type: schema.TypeString, Optional: true},
+			"server": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"address":  {Type: schema.TypeString, Required: true},
+				"key":      {Type: schema.TypeString, Optional: true},
+				"labels":   {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				"password": {Type: schema.TypeString, Optional: true},
+				"port":     {Type: schema.TypeInt, Optional: true},
+				"username": {Type: schema.TypeString, Optional: true},
+			}}},
+			"service_account_name": {Type: schema.TypeString, Optional: true},
+			"shared_secret":        {Type: schema.TypeString, Optional: true},
+			"ssh_certificate":      {Type: schema.TypeString, Optional: true},
+			"ssl_mode":             {Type: schema.TypeString, Optional: true},
+			"sub_system_name":      {Type: schema.TypeString, Optional: true},
+			"tags":                 {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"targets": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"domain":   {Type: schema.TypeString, Optional: true},
+				"host":     {Type: schema.TypeString, Required: true},
+				"id":       {Type: schema.TypeString, Required: true},
+				"name":     {Type: schema.TypeString, Optional: true},
+				"password": {Type: schema.TypeString, Required: true},
+				"port":     {Type: schema.TypeInt, Optional: true},
+				"record":   {Type: schema.TypeBool, Optional: true},
+				"username": {Type: schema.TypeString, Required: true},
+			}}},
+			"tenant_id":                 {Type: schema.TypeString, Optional: true},
+			"tls_cert_file":             {Type: schema.TypeString, Optional: true},
+			"tls_cert_file_fingerprint": {Type: schema.TypeString, Computed: true},
+			"tls_cert_file_not_after":   {Type: schema.TypeString, Computed: true},
+			"tls_key_file":              {Type: schema.TypeString, Optional: true},
+			"tls_root_cert":             {Type: schema.TypeString, Optional: true},
+			"tls_root_cert_fingerprint": {Type: schema.TypeString, Computed: true},
+			"tls_root_cert_not_after":   {Type: schema.TypeString, Computed: true},
+			"token_id":                  {Type: schema.TypeString, Optional: true},
+			"toleration": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"effect":             {Type: schema.TypeString, Optional: true},
+				"key":                {Type: schema.TypeString, Optional: true},
+				"operator":           {Type: schema.TypeString, Optional: true},
+				"toleration_seconds": {Type: schema.TypeInt, Optional: true},
+				"value":              {Type: schema.TypeString, Optional: true},
+			}}},
+			"tolerations":         {Type: schema.TypeString, Optional: true},
+			"type":                {Type: schema.TypeString, Required: true},
+			"uri":                 {Type: schema.TypeString, Optional: true},
+			"url":                 {Type: schema.TypeString, Optional: true},
+			"urls":                {Type: schema.TypeString, Optional: true},
+			"use_proxy":           {Type: schema.TypeBool, Optional: true},
+			"use_service_account": {Type: schema.TypeBool, Optional: true},
+			"use_tenant":          {Type: schema.TypeBool, Optional: true},
+			"username":            {Type: schema.TypeString, Optional: true},
+			"version":             {Type: schema.TypeString, Optional: true},
+			"warehouse":           {Type: schema.TypeString, Optional: true},
+			"webui_port":          {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+// resourceAdaptiveResourceStateUpgradeV1 moves values held only by a
+// deprecated alias to its canonical attribute and drops the aliases from
+// state. Configurations still using an alias plan no change, see
+// suppressUpgradedAlias.
+func resourceAdaptiveResourceStateUpgradeV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	for _, canonical := range integrations.CanonicalAttributes() {
+		for _, alias := range integrations.AttributeAliases[canonical] {
+			if v, _ := rawState[alias].(string); v != "" {
+				if c, _ := rawState[canonical].(string); c == "" {
+					rawState[canonical] = v
+				}
+			}
+			delete(rawState, alias)
+		}
+	}
+	return rawState, nil
+}
+
+// suppressUpgradedAlias hides the diff of a deprecated alias that
+// resourceAdaptiveResourceStateUpgradeV1 dropped from state while the
+// configuration still sets it to the value its canonical attribute holds.
+func suppressUpgradedAlias(canonical string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		prior, _ := d.GetChange(canonical)
+		return old == "" && new != "" && prior.(string) == new
+	}
+}
@@ -0,0 +1,136 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adaptive-scale/terraform-provider-adaptive/internal/provider/integrations"
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// addTLSMaterialSchema adds the computed fingerprint and expiry attributes of
+// every TLS material attribute.
+func addTLSMaterialSchema(s map[string]*schema.Schema) {
+	for _, attr := range integrations.TLSMaterialAttributes {
+		s[attr+"_fingerprint"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("SHA-256 fingerprint of the first certificate in `%s`.", attr),
+		}
+		s[attr+"_not_after"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("Earliest expiry (RFC 3339) of the unexpired certificates in `%s`.", attr),
+		}
+	}
+}
+
+// customizeDiffTLSMaterial parses the TLS material locally, rejecting
+// malformed or expired certificates, misordered chains and a `tls_key_file`
+// that does not match `tls_cert_file`, and plans the computed fingerprint
+// and expiry attributes. CustomizeDiff cannot warn, so certificates expiring
+// within the provider's certificate_expiry_warning_days are only logged
+// here; tlsExpiryWarnings reports them on refresh and after apply.
+func customizeDiffTLSMaterial(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	now := time.Now()
+	days := certificateExpiryWarningDays(m)
+	for _, attr := range integrations.TLSMaterialAttributes {
+		if !d.NewValueKnown(attr) {
+			if err := d.SetNewComputed(attr + "_fingerprint"); err != nil {
+				return err
+			}
+			if err := d.SetNewComputed(attr + "_not_after"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var material integrations.TLSMaterial
+		if value, _ := d.Get(attr).(string); value != "" {
+			var err error
+			if material, err = integrations.InspectTLSMaterial(attr, value, now); err != nil {
+				return err
+			}
+		}
+		if days > 0 && material.ExpiresWithin(now, time.Duration(days)*24*time.Hour) {
+			tflog.Warn(ctx, "Certificate expires soon", map[string]interface{}{
+				"attribute": attr,
+				"not_after": material.NotAfterString(),
+			})
+		}
+		if err := d.SetNew(attr+"_fingerprint", material.Fingerprint); err != nil {
+			return err
+		}
+		if err := d.SetNew(attr+"_not_after", material.NotAfterString()); err != nil {
+			return err
+		}
+	}
+
+	if !newValuesKnown(d, "tls_cert_file", "tls_key_file") {
+		return nil
+	}
+	cert, _ := d.Get("tls_cert_file").(string)
+	key, _ := d.Get("tls_key_file").(string)
+	switch {
+	case cert != "" && key != "":
+		return integrations.CheckTLSKeyPair("tls_cert_file", "tls_key_file", cert, key)
+	case key != "":
+		return fmt.Errorf("`tls_key_file` requires `tls_cert_file`")
+	}
+	return nil
+}
+
+// certificateExpiryWarningDays returns the configured provider's
+// certificate_expiry_warning_days, or 0, disabling the warning, when there is
+// no configured provider.
+func certificateExpiryWarningDays(m interface{}) int {
+	if client, ok := m.(*adaptive.Client); ok && client != nil {
+		return client.Options.CertificateExpiryWarningDays
+	}
+	return 0
+}
+
+// tlsExpiryWarnings warns about expired certificates in a CA bundle that
+// still holds a valid one, and about certificates expiring within the
+// provider's certificate_expiry_warning_days. Read returns them, so they show
+// on refresh, including the one at the start of every plan, and after the
+// apply that sets new material. Invalid material is left to
+// customizeDiffTLSMaterial, which rejects it.
+func tlsExpiryWarnings(d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	days := certificateExpiryWarningDays(m)
+	now := time.Now()
+	var diags diag.Diagnostics
+	for _, attr := range integrations.TLSMaterialAttributes {
+		value, _ := d.Get(attr).(string)
+		if value == "" {
+			continue
+		}
+		material, err := integrations.InspectTLSMaterial(attr, value, now)
+		if err != nil {
+			continue
+		}
+		path := cty.GetAttrPath(attr)
+		for _, expired := range material.Expired {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       fmt.Sprintf("Expired certificate in %s", attr),
+				Detail:        fmt.Sprintf("The CA bundle in `%s` holds a %s. It is still accepted because other certificates in the bundle are valid.", attr, expired),
+				AttributePath: path,
+			})
+		}
+		if days > 0 && material.ExpiresWithin(now, time.Duration(days)*24*time.Hour) {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       fmt.Sprintf("Certificate in %s expires soon", attr),
+				Detail:        fmt.Sprintf("A certificate in `%s` expires on %s, within %d days.", attr, material.NotAfterString(), days),
+				AttributePath: path,
+			})
+		}
+	}
+	return diags
+}
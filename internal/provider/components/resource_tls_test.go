@@ -0,0 +1,69 @@
+package components
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testCertificatePEM(t *testing.T, cn string, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// Expiry warnings use the warning window of the provider that manages the
+// resource, so aliases with different settings do not affect each other.
+func TestTLSExpiryWarnings(t *testing.T) {
+	now := time.Now()
+	soon := testCertificatePEM(t, "soon", now.Add(10*24*time.Hour))
+	later := testCertificatePEM(t, "later", now.Add(365*24*time.Hour))
+	expired := testCertificatePEM(t, "expired", now.Add(-time.Hour))
+	warn := func(rootCert string, days int) diag.Diagnostics {
+		d := schema.TestResourceDataRaw(t, ResourceAdaptiveResource().Schema, map[string]interface{}{
+			"name": "db", "type": "postgres", "root_cert": rootCert,
+		})
+		client := adaptive.NewClient("test-token", "http://localhost")
+		client.Options.CertificateExpiryWarningDays = days
+		return tlsExpiryWarnings(d, client)
+	}
+
+	if diags := warn(soon, 30); len(diags) != 1 || diags[0].Summary != "Certificate in root_cert expires soon" {
+		t.Errorf("expiring certificate: got %+v", diags)
+	}
+	if diags := warn(soon, 5); len(diags) != 0 {
+		t.Errorf("expiring outside a shorter window: got %+v", diags)
+	}
+	if diags := warn(later, 30); len(diags) != 0 {
+		t.Errorf("valid certificate: got %+v", diags)
+	}
+	if diags := warn(expired+later, 30); len(diags) != 1 || diags[0].Summary != "Expired certificate in root_cert" {
+		t.Errorf("bundle with an expired certificate: got %+v", diags)
+	}
+	if diags := warn(soon, 0); len(diags) != 0 {
+		t.Errorf("disabled warning: got %+v", diags)
+	}
+}
@@ -3,10 +3,12 @@ package components
 import (
 	"context"
 	"fmt"
+	"time"
 
 	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func ResourceAdaptiveSchedule() *schema.Resource {
@@ -15,6 +17,7 @@ func ResourceAdaptiveSchedule() *schema.Resource {
 		ReadContext:   ResourceAdaptiveScheduleRead,
 		UpdateContext: ResourceAdaptiveScheduleUpdate,
 		DeleteContext: ResourceAdaptiveScheduleDelete,
+		CustomizeDiff: resourceAdaptiveScheduleCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -28,9 +31,10 @@ func ResourceAdaptiveSchedule() *schema.Resource {
 				Description: "Human-readable description of the schedule.",
 			},
 			"schedule_type": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "One of: weekdays, weekends, everyday, monthly, specific, custom.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(scheduleTypes, false),
+				Description:  "One of: weekdays, weekends, everyday, monthly, specific, custom, rrule.",
 			},
 			"is_active": {
 				Type:        schema.TypeBool,
@@ -42,53 +46,87 @@ func ResourceAdaptiveSchedule() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				Description: "Run the full day, ignoring the start/end time-of-day fields.",
+				Description: "Run the full day, ignoring the start/end time-of-day fields. Setting those fields as well produces a warning after apply and on refresh, not at plan.",
 			},
 			"start_hour": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     0,
-				Description: "Window start hour (0-23). Ignored when all_day is true.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 23),
+				Description:  "Window start hour (0-23). Ignored when all_day is true.",
 			},
 			"start_minute": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     0,
-				Description: "Window start minute (0-59). Ignored when all_day is true.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 59),
+				Description:  "Window start minute (0-59). Ignored when all_day is true.",
 			},
 			"end_hour": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     0,
-				Description: "Window end hour (0-23). Ignored when all_day is true.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 23),
+				Description:  "Window end hour (0-23). Ignored when all_day is true.",
 			},
 			"end_minute": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     0,
-				Description: "Window end minute (0-59). Ignored when all_day is true.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 59),
+				Description:  "Window end minute (0-59). Ignored when all_day is true.",
 			},
+			"window": scheduleWindowSchema(),
 			"weekdays": {
 				Type:        schema.TypeList,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice(weekdayNames, false)},
 				Optional:    true,
-				Description: "Weekday names (e.g. Monday). Used by schedule_type = custom.",
+				Description: "Weekday names (e.g. Monday). Required by schedule_type = custom and rejected at plan on any other type (earlier versions accepted and ignored it).",
 			},
 			"start_day": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "Start day of month (1-31). Used by schedule_type = monthly.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 31),
+				Description:  "Start day of month (1-31). Required by schedule_type = monthly and rejected at plan on any other type (earlier versions accepted and ignored it).",
 			},
 			"end_day": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "End day of month (1-31). Used by schedule_type = monthly.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 31),
+				Description:  "End day of month (1-31). Required by schedule_type = monthly and rejected at plan on any other type (earlier versions accepted and ignored it).",
 			},
 			"specific_dates": {
 				Type:        schema.TypeList,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsRFC3339Time, DiffSuppressFunc: suppressEquivalentRFC3339},
 				Optional:    true,
-				Description: "RFC3339 timestamps. Used by schedule_type = specific.",
+				Description: "RFC3339 timestamps. Required by schedule_type = specific and rejected at plan on any other type (earlier versions accepted and ignored it).",
+			},
+			"rrule": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "RFC 5545 recurrence: a DTSTART line and an RRULE line, such as \"DTSTART:20250106\\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO\". The rule picks the days; the time of day comes from the start and end fields. Required by schedule_type = rrule and rejected at plan on any other type (earlier versions accepted and ignored it).",
+			},
+			"exclude_dates": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateExcludeDate},
+				Optional:    true,
+				Description: "Dates (YYYY-MM-DD, in the schedule timezone) whose window is skipped.",
+			},
+			"holiday_calendar": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "iCalendar (RFC 5545) content, such as file(\"holidays.ics\"). Days covered by its events, including recurring ones, are skipped.",
+			},
+			"expansion_horizon_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(30, 1464),
+				Description:  "How many days ahead an rrule, exclude_dates or holiday_calendar schedule is expanded into specific dates. Unset means 365.",
+			},
+			"expanded_until": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Last date (YYYY-MM-DD) the expanded schedule covers. Empty when the schedule is sent as-is. Once less than half the horizon remains, the next plan re-expands it.",
 			},
 			"users": {
 				Type:        schema.TypeList,
@@ -108,10 +146,18 @@ func ResourceAdaptiveSchedule() *schema.Resource {
 				Optional:    true,
 				Description: "Endpoint (session) names this schedule applies to.",
 			},
+			"mapped_endpoints": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "Endpoints the backend resolved this schedule to. Compare with `endpoints` to check every name resolved.",
+			},
 			"expires_at": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "RFC3339 instant after which the schedule stops applying.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentRFC3339,
+				Description:      "RFC3339 instant after which the schedule stops applying. Setting or changing it to a past instant fails at plan; once an applied value passes, refresh produces a warning.",
 			},
 			"max_access_time": {
 				Type:        schema.TypeInt,
@@ -119,9 +165,10 @@ func ResourceAdaptiveSchedule() *schema.Resource {
 				Description: "Maximum access time in minutes for sessions approved under this schedule.",
 			},
 			"timezone": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "IANA timezone the window is evaluated in. Empty inherits the workspace default.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTimezone,
+				Description:  "IANA timezone the window is evaluated in. Empty inherits the workspace default.",
 			},
 			"operation_type": {
 				Type:        schema.TypeString,
@@ -169,11 +216,16 @@ func scheduleRequestFromSchema(d *schema.ResourceData) (*adaptive.ScheduleReques
 		EndDay:        d.Get("end_day").(int),
 		Weekdays:      stringList(d, "weekdays"),
 		SpecificDates: stringList(d, "specific_dates"),
+		Windows:       scheduleWindowsFromList(d.Get("window").([]interface{})),
 		Users:         stringList(d, "users"),
 		Teams:         stringList(d, "teams"),
 		Endpoints:     stringList(d, "endpoints"),
 		Timezone:      d.Get("timezone").(string),
 		OperationType: d.Get("operation_type").(string),
+
+		RRule:           d.Get("rrule").(string),
+		ExcludeDates:    stringList(d, "exclude_dates"),
+		HolidayCalendar: d.Get("holiday_calendar").(string),
 	}
 
 	if v := d.Get("expires_at").(string); v != "" {
@@ -186,6 +238,35 @@ func scheduleRequestFromSchema(d *schema.ResourceData) (*adaptive.ScheduleReques
 	return req, nil
 }
 
+// scheduleWireRequest returns the request to send to the backend, which has
+// no native recurrence rules or exclusions: a schedule using them is expanded
+// into specific dates over expansion_horizon_days, and the last date covered
+// is returned for expanded_until. Other schedules are sent as they are.
+func scheduleWireRequest(d *schema.ResourceData, req *adaptive.ScheduleRequest, now time.Time) (*adaptive.ScheduleRequest, string, error) {
+	if !req.UsesLocalExpansion() {
+		return req, "", nil
+	}
+	horizon := scheduleExpansionHorizon(d.Get("expansion_horizon_days").(int))
+	expanded, err := req.ExpandRecurrence(now, horizon)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(expanded.SpecificDates) == 0 {
+		return nil, "", fmt.Errorf("schedule %q has no windows in the next %d days; check rrule, exclude_dates and holiday_calendar, or raise expansion_horizon_days", req.Name, horizon)
+	}
+	return expanded, now.AddDate(0, 0, horizon).Format("2006-01-02"), nil
+}
+
+// scheduleExpansionHorizon returns expansion_horizon_days, or 365 when it is
+// unset. The attribute has no schema default so that adding it did not plan
+// a change on every existing schedule.
+func scheduleExpansionHorizon(days int) int {
+	if days == 0 {
+		return defaultExpansionHorizonDays
+	}
+	return days
+}
+
 // stringList reads a TypeList of strings, rejecting empty entries.
 func stringList(d *schema.ResourceData, key string) []string {
 	raw := d.Get(key).([]interface{})
@@ -206,12 +287,26 @@ func ResourceAdaptiveScheduleCreate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	resp, err := client.CreateSchedule(ctx, req)
+	wire, expandedUntil, err := scheduleWireRequest(d, req, time.Now())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := client.CreateSchedule(ctx, wire)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(resp.ID)
-	return nil
+	if err := d.Set("mapped_endpoints", resp.MappedEndpoints); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("expanded_until", expandedUntil); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := verifyScheduleWindows(ctx, client, d.Id(), wire); err != nil {
+		return diag.FromErr(err)
+	}
+	return scheduleWarnings(d)
 }
 
 func ResourceAdaptiveScheduleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -226,28 +321,44 @@ func ResourceAdaptiveScheduleRead(ctx context.Context, d *schema.ResourceData, m
 		d.SetId("")
 		return nil
 	}
-	// The read endpoint returns a lossy view (no pattern fields), so we only
-	// refresh attributes it authoritatively reports to avoid spurious diffs.
-	if err := d.Set("name", resp.Name); err != nil {
+	if err := checkScheduleWindowsStored(d.Get("name").(string), len(d.Get("window").([]interface{})), resp); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("schedule_type", resp.ScheduleType); err != nil {
+	if err := setScheduleState(d, resp); err != nil {
 		return diag.FromErr(err)
 	}
+	return scheduleWarnings(d)
+}
+
+// setScheduleState refreshes state from a read response. Fields the backend
+// omits are left as they are: older backends return a lossy view without the
+// pattern, users, teams or endpoints, and clobbering them would plan spurious
+// diffs. A schedule sent expanded into specific dates keeps its configured
+// pattern, since the backend only knows the expansion.
+func setScheduleState(d *schema.ResourceData, resp *adaptive.ScheduleResponse) error {
+	expanded := d.Get("expanded_until").(string) != ""
+	if err := d.Set("name", resp.Name); err != nil {
+		return err
+	}
+	if !expanded {
+		if err := d.Set("schedule_type", resp.ScheduleType); err != nil {
+			return err
+		}
+	}
 	if err := d.Set("is_active", resp.IsActive); err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 	if err := d.Set("all_day", resp.AllDay); err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 	if resp.Timezone != "" {
 		if err := d.Set("timezone", resp.Timezone); err != nil {
-			return diag.FromErr(err)
+			return err
 		}
 	}
 	if resp.OperationType != "" {
 		if err := d.Set("operation_type", resp.OperationType); err != nil {
-			return diag.FromErr(err)
+			return err
 		}
 	}
 	// expires_at is returned by the backend normalized to RFC3339 UTC. Refresh
@@ -255,10 +366,53 @@ func ResourceAdaptiveScheduleRead(ctx context.Context, d *schema.ResourceData, m
 	// backend chose not to report (matches the timezone/operation_type pattern).
 	if resp.ExpiresAt != "" {
 		if err := d.Set("expires_at", resp.ExpiresAt); err != nil {
-			return diag.FromErr(err)
+			return err
 		}
 	}
-	return nil
+
+	if resp.Description != nil {
+		if err := d.Set("description", *resp.Description); err != nil {
+			return err
+		}
+	}
+	for key, v := range map[string]*int{
+		"start_hour":      resp.StartHour,
+		"start_minute":    resp.StartMinute,
+		"end_hour":        resp.EndHour,
+		"end_minute":      resp.EndMinute,
+		"start_day":       resp.StartDay,
+		"end_day":         resp.EndDay,
+		"max_access_time": resp.MaxAccessTime,
+	} {
+		if v == nil || expanded && (key == "start_day" || key == "end_day") {
+			continue
+		}
+		if err := d.Set(key, *v); err != nil {
+			return err
+		}
+	}
+	for key, v := range map[string]*[]string{
+		"weekdays":       resp.Weekdays,
+		"specific_dates": resp.SpecificDates,
+		"users":          resp.Users,
+		"teams":          resp.Teams,
+		"endpoints":      resp.Endpoints,
+	} {
+		if v == nil || expanded && (key == "weekdays" || key == "specific_dates") {
+			continue
+		}
+		if err := d.Set(key, *v); err != nil {
+			return err
+		}
+	}
+
+	if resp.Windows != nil {
+		if err := d.Set("window", flattenScheduleWindows(*resp.Windows)); err != nil {
+			return err
+		}
+	}
+
+	return d.Set("mapped_endpoints", resp.MappedEndpoints)
 }
 
 func ResourceAdaptiveScheduleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -269,10 +423,52 @@ func ResourceAdaptiveScheduleUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	if _, err := client.UpdateSchedule(ctx, d.Id(), req); err != nil {
+	wire, expandedUntil, err := scheduleWireRequest(d, req, time.Now())
+	if err != nil {
 		return diag.FromErr(err)
 	}
-	return nil
+
+	resp, err := client.UpdateSchedule(ctx, d.Id(), wire)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("mapped_endpoints", resp.MappedEndpoints); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("expanded_until", expandedUntil); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := verifyScheduleWindows(ctx, client, d.Id(), wire); err != nil {
+		return diag.FromErr(err)
+	}
+	return scheduleWarnings(d)
+}
+
+// verifyScheduleWindows reads back a schedule sent with `window` blocks. A
+// backend without window support ignores them and stores the zeroed
+// single-window fields, 00:00-00:00, without an error; its read view then
+// has no windows either.
+func verifyScheduleWindows(ctx context.Context, client *adaptive.Client, id string, wire *adaptive.ScheduleRequest) error {
+	if len(wire.Windows) == 0 {
+		return nil
+	}
+	resp, err := client.GetSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return fmt.Errorf("schedule %q was not found after it was written", wire.Name)
+	}
+	return checkScheduleWindowsStored(wire.Name, len(wire.Windows), resp)
+}
+
+// checkScheduleWindowsStored fails when a schedule with want window blocks is
+// read back without any, so the drift to 00:00-00:00 is not hidden.
+func checkScheduleWindowsStored(name string, want int, resp *adaptive.ScheduleResponse) error {
+	if want == 0 || resp.Windows != nil && len(*resp.Windows) > 0 {
+		return nil
+	}
+	return fmt.Errorf("the backend did not return the %d `window` block(s) of schedule %q, so it does not support them and applies 00:00-00:00 instead; use start_hour, start_minute, end_hour and end_minute with this backend", want, name)
 }
 
 func ResourceAdaptiveScheduleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
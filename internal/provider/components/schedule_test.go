@@ -6,9 +6,11 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 // scheduleRequestFromSchema must carry operation_type through to the API request
@@ -75,3 +77,444 @@ func TestResourceAdaptiveScheduleRead_RefreshesExpiresAt(t *testing.T) {
 		t.Errorf("expires_at not refreshed from read response: got %q want %q", got, want)
 	}
 }
+
+func TestResourceAdaptiveScheduleCustomizeDiff(t *testing.T) {
+	res := ResourceAdaptiveSchedule()
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{name: "custom with weekdays", config: map[string]interface{}{"schedule_type": "custom", "weekdays": []interface{}{"Monday", "Friday"}}},
+		{name: "custom without weekdays", config: map[string]interface{}{"schedule_type": "custom"}, wantErr: "requires `weekdays`"},
+		{name: "duplicate weekday", config: map[string]interface{}{"schedule_type": "custom", "weekdays": []interface{}{"Monday", "Monday"}}, wantErr: "more than once"},
+		{name: "weekdays on weekends", config: map[string]interface{}{"schedule_type": "weekends", "weekdays": []interface{}{"Monday"}}, wantErr: "only used by schedule_type = \"custom\""},
+		{name: "monthly", config: map[string]interface{}{"schedule_type": "monthly", "start_day": 1, "end_day": 15}},
+		{name: "monthly without end_day", config: map[string]interface{}{"schedule_type": "monthly", "start_day": 1}, wantErr: "requires `end_day`"},
+		{name: "specific without dates", config: map[string]interface{}{"schedule_type": "specific"}, wantErr: "requires `specific_dates`"},
+		{name: "everyday", config: map[string]interface{}{"schedule_type": "everyday", "start_hour": 9, "end_hour": 17}},
+		{name: "rrule", config: map[string]interface{}{"schedule_type": "rrule", "rrule": "DTSTART:20250106\nRRULE:FREQ=WEEKLY;BYDAY=MO"}},
+		{name: "rrule without rrule", config: map[string]interface{}{"schedule_type": "rrule"}, wantErr: "requires `rrule`"},
+		{name: "rrule on weekdays", config: map[string]interface{}{"schedule_type": "weekdays", "rrule": "DTSTART:20250106\nRRULE:FREQ=DAILY"}, wantErr: "only used by schedule_type = \"rrule\""},
+		{name: "invalid rrule", config: map[string]interface{}{"schedule_type": "rrule", "rrule": "RRULE:FREQ=DAILY"}, wantErr: "DTSTART"},
+		{name: "split windows", config: map[string]interface{}{"schedule_type": "weekdays", "window": []interface{}{
+			map[string]interface{}{"start": "00:00", "end": "06:00"},
+			map[string]interface{}{"start": "18:00", "end": "24:00"},
+		}}},
+		{name: "window with all_day", config: map[string]interface{}{"schedule_type": "weekdays", "all_day": true, "window": []interface{}{
+			map[string]interface{}{"start": "00:00", "end": "06:00"},
+		}}, wantErr: "all_day"},
+		{name: "window with start_hour", config: map[string]interface{}{"schedule_type": "weekdays", "start_hour": 9, "window": []interface{}{
+			map[string]interface{}{"start": "00:00", "end": "06:00"},
+		}}, wantErr: "`start_hour` cannot be combined"},
+		{name: "overlapping windows", config: map[string]interface{}{"schedule_type": "weekdays", "window": []interface{}{
+			map[string]interface{}{"start": "08:00", "end": "12:00"},
+			map[string]interface{}{"start": "11:00", "end": "13:00"},
+		}}, wantErr: "overlaps"},
+		{name: "invalid holiday calendar", config: map[string]interface{}{"schedule_type": "weekdays", "holiday_calendar": "BEGIN:VEVENT"}, wantErr: "`holiday_calendar`"},
+		{name: "future expires_at", config: map[string]interface{}{"schedule_type": "weekdays", "expires_at": "2999-01-01T00:00:00Z"}},
+		{name: "past expires_at", config: map[string]interface{}{"schedule_type": "weekdays", "expires_at": "2001-01-01T00:00:00Z"}, wantErr: "`expires_at` (2001-01-01T00:00:00Z) is in the past"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["name"] = "s"
+			_, err := res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// A schedule that expired after it was applied must still plan; only setting
+// or changing expires_at to a past instant is rejected.
+func TestResourceAdaptiveScheduleCustomizeDiff_ExpiredSinceApply(t *testing.T) {
+	res := ResourceAdaptiveSchedule()
+	state := &terraform.InstanceState{ID: "sch-1", Attributes: map[string]string{
+		"name":           "s",
+		"schedule_type":  "weekdays",
+		"is_active":      "true",
+		"all_day":        "false",
+		"start_hour":     "0",
+		"start_minute":   "0",
+		"end_hour":       "0",
+		"end_minute":     "0",
+		"operation_type": "autoapprove",
+		"expires_at":     "2001-01-01T00:00:00Z",
+	}}
+	cfg := map[string]interface{}{"name": "s", "schedule_type": "weekdays", "expires_at": "2001-01-01T00:00:00Z"}
+	if _, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(cfg), nil); err != nil {
+		t.Fatalf("unchanged expires_at: %v", err)
+	}
+	cfg["expires_at"] = "2002-01-01T00:00:00Z"
+	if _, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(cfg), nil); err == nil || !strings.Contains(err.Error(), "in the past") {
+		t.Fatalf("changed expires_at: error = %v, want one about the past", err)
+	}
+}
+
+func TestResourceAdaptiveScheduleFieldValidation(t *testing.T) {
+	res := ResourceAdaptiveSchedule()
+	tests := []struct {
+		config map[string]interface{}
+		valid  bool
+	}{
+		{config: map[string]interface{}{"schedule_type": "fortnightly"}},
+		{config: map[string]interface{}{"schedule_type": "weekdays", "start_hour": 99}},
+		{config: map[string]interface{}{"schedule_type": "weekdays", "end_minute": 60}},
+		{config: map[string]interface{}{"schedule_type": "monthly", "start_day": 0, "end_day": 32}},
+		{config: map[string]interface{}{"schedule_type": "custom", "weekdays": []interface{}{"Funday"}}},
+		{config: map[string]interface{}{"schedule_type": "specific", "specific_dates": []interface{}{"2030-01-01"}}},
+		{config: map[string]interface{}{"schedule_type": "weekdays", "expires_at": "tomorrow"}},
+		{config: map[string]interface{}{"schedule_type": "weekdays", "timezone": "Mars/Olympus_Mons"}},
+		{config: map[string]interface{}{"schedule_type": "weekdays", "exclude_dates": []interface{}{"2030-12-25T00:00:00Z"}}},
+		{config: map[string]interface{}{"schedule_type": "weekdays", "expansion_horizon_days": 5000}},
+		{config: map[string]interface{}{"schedule_type": "weekdays", "timezone": "America/New_York", "expires_at": "2030-12-31T23:59:59Z"}, valid: true},
+		{config: map[string]interface{}{"schedule_type": "weekdays", "exclude_dates": []interface{}{"2030-12-25"}}, valid: true},
+	}
+	for _, tt := range tests {
+		tt.config["name"] = "s"
+		diags := res.Validate(terraform.NewResourceConfigRaw(tt.config))
+		if diags.HasError() == tt.valid {
+			t.Errorf("%v: valid = %v, diagnostics %v", tt.config, !diags.HasError(), diags)
+		}
+	}
+}
+
+func TestScheduleWarnings(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSchedule().Schema, map[string]interface{}{
+		"name":          "old",
+		"schedule_type": "weekdays",
+		"all_day":       true,
+		"start_hour":    9,
+		"expires_at":    "2001-01-01T00:00:00Z",
+	})
+	diags := scheduleWarnings(d)
+	if len(diags) != 2 || diags.HasError() {
+		t.Fatalf("expected two warnings, got %+v", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "start_hour") || !strings.Contains(diags[1].Summary, "expired") {
+		t.Errorf("unexpected warnings: %+v", diags)
+	}
+}
+
+// A full read view must refresh the pattern and membership so drift is
+// detected, and mapped_endpoints must reflect what the backend resolved.
+func TestResourceAdaptiveScheduleRead_FullView(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("view") != "full" {
+			http.Error(w, "expected the full view", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "sch-1",
+			"name": "oncall",
+			"scheduleType": "custom",
+			"isActive": true,
+			"description": "changed in the UI",
+			"startHour": 8,
+			"startMinute": 30,
+			"endHour": 18,
+			"endMinute": 0,
+			"weekdays": ["Monday", "Tuesday"],
+			"windows": [{"startHour": 0, "startMinute": 0, "endHour": 6, "endMinute": 0}, {"startHour": 18, "startMinute": 0, "endHour": 24, "endMinute": 0, "weekdays": ["Monday"]}],
+			"users": ["a@example.com"],
+			"teams": [],
+			"endpoints": ["prod-db"],
+			"mappedEndpoints": ["prod-db"]
+		}`))
+	}))
+	defer srv.Close()
+
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSchedule().Schema, map[string]interface{}{
+		"name":            "oncall",
+		"schedule_type":   "custom",
+		"description":     "on-call window",
+		"start_hour":      9,
+		"weekdays":        []interface{}{"Monday"},
+		"teams":           []interface{}{"sre"},
+		"endpoints":       []interface{}{"prod-db"},
+		"max_access_time": 60,
+	})
+	d.SetId("sch-1")
+
+	if diags := ResourceAdaptiveScheduleRead(context.Background(), d, adaptive.NewClient("test-token", srv.URL)); diags.HasError() {
+		t.Fatalf("read returned diagnostics: %+v", diags)
+	}
+
+	if got := d.Get("description").(string); got != "changed in the UI" {
+		t.Errorf("description = %q", got)
+	}
+	if got := d.Get("start_hour").(int); got != 8 {
+		t.Errorf("start_hour = %d, want 8", got)
+	}
+	if got := d.Get("start_minute").(int); got != 30 {
+		t.Errorf("start_minute = %d, want 30", got)
+	}
+	if got := d.Get("weekdays").([]interface{}); len(got) != 2 {
+		t.Errorf("weekdays = %v", got)
+	}
+	if got := d.Get("window").([]interface{}); len(got) != 2 || got[1].(map[string]interface{})["end"] != "24:00" {
+		t.Errorf("window = %v", got)
+	}
+	if got := d.Get("teams").([]interface{}); len(got) != 0 {
+		t.Errorf("teams = %v, want the empty list the backend reported", got)
+	}
+	if got := d.Get("mapped_endpoints").([]interface{}); len(got) != 1 || got[0] != "prod-db" {
+		t.Errorf("mapped_endpoints = %v", got)
+	}
+	// Not reported, so left alone.
+	if got := d.Get("max_access_time").(int); got != 60 {
+		t.Errorf("max_access_time = %d, want the unreported value kept", got)
+	}
+}
+
+// A backend without window support stores 00:00-00:00 without an error, so
+// window blocks must fail at apply and refresh when they are not read back.
+func TestResourceAdaptiveSchedule_WindowsNotStored(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "sch-1", "name": "split", "scheduleType": "weekdays", "isActive": true, "startHour": 0, "endHour": 0}`))
+	}))
+	defer srv.Close()
+
+	client := adaptive.NewClient("test-token", srv.URL)
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSchedule().Schema, map[string]interface{}{
+		"name":          "split",
+		"schedule_type": "weekdays",
+		"window": []interface{}{
+			map[string]interface{}{"start": "00:00", "end": "06:00"},
+			map[string]interface{}{"start": "18:00", "end": "24:00"},
+		},
+	})
+	diags := ResourceAdaptiveScheduleCreate(context.Background(), d, client)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "did not return the 2 `window` block(s)") {
+		t.Fatalf("create diagnostics = %+v, want an error about the window blocks", diags)
+	}
+	if d.Id() != "sch-1" {
+		t.Errorf("created schedule not kept in state: id %q", d.Id())
+	}
+	if diags := ResourceAdaptiveScheduleRead(context.Background(), d, client); !diags.HasError() {
+		t.Fatal("read kept the configured window blocks the backend dropped")
+	}
+}
+
+func TestSuppressEquivalentRFC3339(t *testing.T) {
+	if !suppressEquivalentRFC3339("expires_at", "2030-01-01T00:00:00Z", "2030-01-01T01:00:00+01:00", nil) {
+		t.Error("expected equal instants to be suppressed")
+	}
+	if suppressEquivalentRFC3339("expires_at", "2030-01-01T00:00:00Z", "2030-01-01T00:00:01Z", nil) {
+		t.Error("expected different instants to diff")
+	}
+}
+
+// Recurrence rules and exclusions are expanded into specific dates on the
+// wire, and expanded_until is planned so the expansion is refreshed.
+func TestScheduleWireRequest(t *testing.T) {
+	res := ResourceAdaptiveSchedule()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"name":                   "biweekly",
+		"schedule_type":          "rrule",
+		"rrule":                  "DTSTART:20250106\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+		"exclude_dates":          []interface{}{"2025-01-20"},
+		"expansion_horizon_days": 40,
+	})
+	req, err := scheduleRequestFromSchema(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wire, until, err := scheduleWireRequest(d, req, mustParseTime(t, "2025-01-07T12:00:00Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wire.ScheduleType != "specific" || len(wire.SpecificDates) != 1 || wire.SpecificDates[0] != "2025-02-03T00:00:00Z" {
+		t.Errorf("wire request = %+v", wire)
+	}
+	if until != "2025-02-16" {
+		t.Errorf("expanded_until = %q, want 2025-02-16", until)
+	}
+
+	// Unset, the horizon is 365 days.
+	d = schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"name":          "default",
+		"schedule_type": "weekdays",
+		"exclude_dates": []interface{}{"2025-01-20"},
+	})
+	req, _ = scheduleRequestFromSchema(d)
+	if _, until, err := scheduleWireRequest(d, req, mustParseTime(t, "2025-01-07T12:00:00Z")); err != nil || until != "2026-01-07" {
+		t.Errorf("default horizon: expanded_until = %q, %v; want 2026-01-07", until, err)
+	}
+
+	d = schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"name":          "never",
+		"schedule_type": "rrule",
+		"rrule":         "DTSTART:20200106\nRRULE:FREQ=DAILY;COUNT=1",
+	})
+	req, _ = scheduleRequestFromSchema(d)
+	if _, _, err := scheduleWireRequest(d, req, mustParseTime(t, "2025-01-07T12:00:00Z")); err == nil || !strings.Contains(err.Error(), "no windows") {
+		t.Errorf("error = %v, want one about no windows", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"name": "plain", "schedule_type": "weekdays"})
+	req, _ = scheduleRequestFromSchema(d)
+	if wire, until, err := scheduleWireRequest(d, req, mustParseTime(t, "2025-01-07T12:00:00Z")); err != nil || wire != req || until != "" {
+		t.Errorf("plain schedule changed on the wire: %+v, %q, %v", wire, until, err)
+	}
+}
+
+func TestResourceAdaptiveScheduleCustomizeDiff_Expansion(t *testing.T) {
+	res := ResourceAdaptiveSchedule()
+	cfg := map[string]interface{}{"name": "s", "schedule_type": "weekdays", "exclude_dates": []interface{}{"2030-12-25"}}
+	diff, err := res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(cfg), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attr := diff.Attributes["expanded_until"]; attr == nil || !attr.NewComputed {
+		t.Errorf("expanded_until not planned as computed: %+v", attr)
+	}
+
+	// An unchanged schedule is re-expanded once half its horizon has passed.
+	stale := &terraform.InstanceState{ID: "sch-1", Attributes: map[string]string{
+		"name":               "s",
+		"schedule_type":      "weekdays",
+		"is_active":          "true",
+		"all_day":            "false",
+		"start_hour":         "0",
+		"start_minute":       "0",
+		"end_hour":           "0",
+		"end_minute":         "0",
+		"operation_type":     "autoapprove",
+		"exclude_dates.#":    "1",
+		"exclude_dates.0":    "2030-12-25",
+		"mapped_endpoints.#": "0",
+	}}
+	for until, wantReplan := range map[string]bool{
+		time.Now().AddDate(0, 0, 100).Format("2006-01-02"): true,
+		time.Now().AddDate(0, 0, 300).Format("2006-01-02"): false,
+	} {
+		stale.Attributes["expanded_until"] = until
+		diff, err = res.Diff(context.Background(), stale, terraform.NewResourceConfigRaw(cfg), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		replanned := diff != nil && diff.Attributes["expanded_until"] != nil && diff.Attributes["expanded_until"].NewComputed
+		if replanned != wantReplan {
+			t.Errorf("expanded_until %s: replanned = %v, want %v", until, replanned, wantReplan)
+		}
+	}
+
+	// Dropping the exclusions sends the schedule as-is again.
+	state := &terraform.InstanceState{ID: "sch-1", Attributes: map[string]string{
+		"name":            "s",
+		"schedule_type":   "weekdays",
+		"is_active":       "true",
+		"operation_type":  "autoapprove",
+		"exclude_dates.#": "1",
+		"exclude_dates.0": "2030-12-25",
+		"expanded_until":  "2031-01-01",
+	}}
+	delete(cfg, "exclude_dates")
+	diff, err = res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(cfg), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attr := diff.Attributes["expanded_until"]; attr == nil || !attr.NewComputed {
+		t.Errorf("expanded_until not replanned: %+v", attr)
+	}
+}
+
+// The backend only knows the expansion of an rrule schedule, so a read must
+// not replace the configured pattern with it.
+func TestResourceAdaptiveScheduleRead_ExpandedKeepsPattern(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "sch-1", "name": "biweekly", "scheduleType": "specific", "isActive": true, "specificDates": ["2025-02-03T00:00:00Z"]}`))
+	}))
+	defer srv.Close()
+
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSchedule().Schema, map[string]interface{}{
+		"name":          "biweekly",
+		"schedule_type": "rrule",
+		"rrule":         "DTSTART:20250106\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+	})
+	d.SetId("sch-1")
+	if err := d.Set("expanded_until", "2026-01-07"); err != nil {
+		t.Fatal(err)
+	}
+
+	if diags := ResourceAdaptiveScheduleRead(context.Background(), d, adaptive.NewClient("test-token", srv.URL)); diags.HasError() {
+		t.Fatalf("read returned diagnostics: %+v", diags)
+	}
+	if got := d.Get("schedule_type").(string); got != "rrule" {
+		t.Errorf("schedule_type = %q, want rrule", got)
+	}
+	if got := d.Get("specific_dates").([]interface{}); len(got) != 0 {
+		t.Errorf("specific_dates = %v, want the expansion kept out of state", got)
+	}
+}
+
+func mustParseTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestCheckScheduleWindows(t *testing.T) {
+	everyDay := weekdayNames
+	tests := []struct {
+		name    string
+		windows []adaptive.ScheduleTimeWindow
+		days    []string
+		wantErr string
+	}{
+		{name: "touching", windows: []adaptive.ScheduleTimeWindow{{StartHour: 0, EndHour: 6}, {StartHour: 6, EndHour: 12}}, days: everyDay},
+		{name: "whole day", windows: []adaptive.ScheduleTimeWindow{{StartHour: 0, EndHour: 24}}, days: everyDay},
+		{name: "empty", windows: []adaptive.ScheduleTimeWindow{{StartHour: 9, EndHour: 9}}, days: everyDay, wantErr: "is empty"},
+		{
+			name:    "overnight into the next morning",
+			windows: []adaptive.ScheduleTimeWindow{{StartHour: 22, EndHour: 6}, {StartHour: 5, EndHour: 8}},
+			days:    everyDay,
+			wantErr: "window 1 (22:00-06:00 on Sunday) overlaps window 2 (05:00-08:00 on Monday)",
+		},
+		{
+			name:    "sunday night wraps into monday",
+			windows: []adaptive.ScheduleTimeWindow{{StartHour: 22, EndHour: 6, Weekdays: []string{"Sunday"}}, {StartHour: 5, EndHour: 8, Weekdays: []string{"Monday"}}},
+			days:    everyDay,
+			wantErr: "on Sunday) overlaps window 2",
+		},
+		{
+			name:    "different weekdays do not overlap",
+			windows: []adaptive.ScheduleTimeWindow{{StartHour: 9, EndHour: 17, Weekdays: []string{"Monday"}}, {StartHour: 9, EndHour: 17, Weekdays: []string{"Tuesday"}}},
+			days:    everyDay,
+		},
+		{
+			name:    "weekday outside the schedule",
+			windows: []adaptive.ScheduleTimeWindow{{StartHour: 9, EndHour: 17, Weekdays: []string{"Saturday"}}},
+			days:    weekdayNames[:5],
+			wantErr: "the schedule never does",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkScheduleWindows(tt.windows, tt.days)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	scheduleTypes = []string{"weekdays", "weekends", "everyday", "monthly", "specific", "custom", "rrule"}
+	weekdayNames  = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+	// scheduleTypeFields are the pattern attributes each is only read by one
+	// schedule_type, which also requires them.
+	scheduleTypeFields = map[string][]string{
+		"custom":   {"weekdays"},
+		"monthly":  {"start_day", "end_day"},
+		"specific": {"specific_dates"},
+		"rrule":    {"rrule"},
+	}
+
+	scheduleTimeOfDayFields = []string{"start_hour", "start_minute", "end_hour", "end_minute"}
+)
+
+const defaultExpansionHorizonDays = 365
+
+// validateTimezone accepts IANA timezone names known to the Go time database.
+func validateTimezone(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s must be a string", k)}
+	}
+	if v == "" {
+		return nil, nil
+	}
+	if _, err := time.LoadLocation(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %q is not an IANA timezone such as Europe/Berlin or America/New_York", k, v)}
+	}
+	return nil, nil
+}
+
+// validateExcludeDate accepts YYYY-MM-DD calendar dates.
+func validateExcludeDate(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s must be a string", k)}
+	}
+	if err := adaptive.ValidateExcludeDate(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// scheduleLocation returns the planned timezone, or UTC when it is empty or
+// not yet valid; validateTimezone reports the latter.
+func scheduleLocation(d *schema.ResourceDiff) *time.Location {
+	if loc, err := time.LoadLocation(d.Get("timezone").(string)); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// resourceAdaptiveScheduleCustomizeDiff checks that the pattern fields match
+// schedule_type: each type-specific field is required by its own type and
+// rejected on the others. It also rejects setting expires_at to a past
+// instant; CustomizeDiff cannot warn, so the all_day warning in
+// scheduleWarnings only shows after apply and on refresh.
+func resourceAdaptiveScheduleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := customizeDiffScheduleExpiry(d, time.Now()); err != nil {
+		return err
+	}
+	if !d.NewValueKnown("schedule_type") {
+		return nil
+	}
+	scheduleType := d.Get("schedule_type").(string)
+
+	for _, owner := range scheduleTypes {
+		fields := scheduleTypeFields[owner]
+		if !newValuesKnown(d, fields...) {
+			continue
+		}
+		for _, field := range fields {
+			set := scheduleFieldSet(d.Get(field))
+			switch {
+			case owner == scheduleType && !set:
+				return fmt.Errorf("schedule_type = %q requires `%s`", scheduleType, field)
+			case owner != scheduleType && set:
+				return fmt.Errorf("`%s` is only used by schedule_type = %q, not %q", field, owner, scheduleType)
+			}
+		}
+	}
+
+	if scheduleType == "custom" && d.NewValueKnown("weekdays") {
+		seen := make(map[string]bool)
+		for _, v := range d.Get("weekdays").([]interface{}) {
+			day, _ := v.(string)
+			if seen[day] {
+				return fmt.Errorf("`weekdays` lists %s more than once", day)
+			}
+			seen[day] = true
+		}
+	}
+
+	if err := customizeDiffScheduleWindows(d); err != nil {
+		return err
+	}
+
+	if newValuesKnown(d, "rrule", "holiday_calendar", "timezone") {
+		loc := scheduleLocation(d)
+		if v := d.Get("rrule").(string); v != "" {
+			if err := adaptive.ParseRecurrenceRule(v, loc); err != nil {
+				return fmt.Errorf("`rrule`: %w", err)
+			}
+		}
+		if v := d.Get("holiday_calendar").(string); v != "" {
+			if err := adaptive.ParseHolidayCalendar(v, loc); err != nil {
+				return fmt.Errorf("`holiday_calendar`: %w", err)
+			}
+		}
+	}
+	return customizeDiffScheduleExpansion(d, time.Now())
+}
+
+// customizeDiffScheduleExpiry rejects an expires_at in the past when it is
+// set or changed. A schedule that has since expired is left alone so it can
+// still be planned; Read warns about it instead.
+func customizeDiffScheduleExpiry(d *schema.ResourceDiff, now time.Time) error {
+	if !d.NewValueKnown("expires_at") || (d.Id() != "" && !d.HasChange("expires_at")) {
+		return nil
+	}
+	v := d.Get("expires_at").(string)
+	if t, err := time.Parse(time.RFC3339, v); err == nil && !t.After(now) {
+		return fmt.Errorf("`expires_at` (%s) is in the past; the schedule would never apply", v)
+	}
+	return nil
+}
+
+// customizeDiffScheduleWindows checks `window` blocks: they replace the
+// single-window fields and all_day, and must not overlap or fall on weekdays
+// the schedule never applies on.
+func customizeDiffScheduleWindows(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("window") {
+		return nil
+	}
+	raw := d.Get("window").([]interface{})
+	if len(raw) == 0 {
+		return nil
+	}
+	if d.NewValueKnown("all_day") && d.Get("all_day").(bool) {
+		return fmt.Errorf("`window` blocks cannot be combined with all_day = true; use a 00:00-24:00 window instead")
+	}
+	for _, field := range scheduleTimeOfDayFields {
+		if d.NewValueKnown(field) && d.Get(field).(int) != 0 {
+			return fmt.Errorf("`%s` cannot be combined with `window` blocks; set the times in the blocks", field)
+		}
+	}
+	windows := scheduleWindowsFromList(raw)
+	if len(windows) != len(raw) || !newValuesKnown(d, "schedule_type", "weekdays") {
+		return nil
+	}
+	return checkScheduleWindows(windows, scheduleTypeWeekdays(d.Get("schedule_type").(string), d.Get("weekdays").([]interface{})))
+}
+
+// customizeDiffScheduleExpansion plans expanded_until for schedules sent to
+// the backend as specific dates. Any change re-expands them, and so does the
+// horizon running down to half, so an unchanged schedule keeps covering the
+// dates ahead as long as it is applied regularly.
+func customizeDiffScheduleExpansion(d *schema.ResourceDiff, now time.Time) error {
+	if !newValuesKnown(d, "schedule_type", "exclude_dates", "holiday_calendar") {
+		return d.SetNewComputed("expanded_until")
+	}
+	current := d.Get("expanded_until").(string)
+	expands := d.Get("schedule_type").(string) == "rrule" ||
+		len(d.Get("exclude_dates").([]interface{})) > 0 ||
+		d.Get("holiday_calendar").(string) != ""
+	if !expands {
+		// The SDK plans an empty computed string as unknown, so clearing it
+		// is planned that way too.
+		if current != "" {
+			return d.SetNewComputed("expanded_until")
+		}
+		return nil
+	}
+	if d.Id() == "" || len(d.GetChangedKeysPrefix("")) > 0 {
+		return d.SetNewComputed("expanded_until")
+	}
+	until, err := time.Parse("2006-01-02", current)
+	if err != nil || now.AddDate(0, 0, scheduleExpansionHorizon(d.Get("expansion_horizon_days").(int))/2).After(until) {
+		return d.SetNewComputed("expanded_until")
+	}
+	return nil
+}
+
+func scheduleFieldSet(v interface{}) bool {
+	switch v := v.(type) {
+	case int:
+		return v != 0
+	case []interface{}:
+		return len(v) > 0
+	case string:
+		return v != ""
+	}
+	return false
+}
+
+// scheduleWarnings flags settings that are accepted but have no effect:
+// time-of-day fields on an all_day schedule and an expires_at that has passed
+// since it was applied. Only Create, Read and Update return them, so they show
+// after apply and on refresh rather than in the first plan.
+func scheduleWarnings(d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if d.Get("all_day").(bool) {
+		var ignored []string
+		for _, field := range scheduleTimeOfDayFields {
+			if d.Get(field).(int) != 0 {
+				ignored = append(ignored, "`"+field+"`")
+			}
+		}
+		if len(ignored) > 0 {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Schedule %q ignores its time of day", d.Get("name").(string)),
+				Detail:   fmt.Sprintf("%s have no effect because all_day is true. Remove them or set all_day = false.", strings.Join(ignored, ", ")),
+			})
+		}
+	}
+	if v := d.Get("expires_at").(string); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil && t.Before(time.Now()) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Schedule %q has expired", d.Get("name").(string)),
+				Detail:   fmt.Sprintf("expires_at (%s) is in the past, so the schedule no longer applies.", v),
+			})
+		}
+	}
+	return diags
+}
+
+// suppressEquivalentRFC3339 ignores differences between timestamps naming the
+// same instant, as when the backend normalizes an offset to UTC.
+func suppressEquivalentRFC3339(k, old, new string, d *schema.ResourceData) bool {
+	o, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	n, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return o.Equal(n)
+}
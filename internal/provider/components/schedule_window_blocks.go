@@ -0,0 +1,219 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):([0-5][0-9])$`)
+
+// scheduleWindowSchema is the repeatable `window` block of adaptive_schedule.
+func scheduleWindowSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "A time-of-day window. Repeat the block for split windows, such as 00:00-06:00 and 18:00-24:00. Replaces start_hour, start_minute, end_hour and end_minute, and cannot be combined with all_day. Windows may touch but not overlap. Requires a backend that stores window blocks: apply and refresh fail if the schedule is read back without them.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"start": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateClock(false),
+					Description:  "Start time, HH:MM from 00:00 to 23:59.",
+				},
+				"end": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateClock(true),
+					Description:  "End time, HH:MM up to 24:00. An end before start runs past midnight into the next day.",
+				},
+				"weekdays": {
+					Type:        schema.TypeList,
+					Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice(weekdayNames, false)},
+					Optional:    true,
+					Description: "Weekday names the window starts on. Empty means every day the schedule applies.",
+				},
+			},
+		},
+	}
+}
+
+// validateClock accepts HH:MM times of day, and 24:00 when allow24 is set.
+func validateClock(allow24 bool) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) ([]string, []error) {
+		v, ok := i.(string)
+		if !ok {
+			return nil, []error{fmt.Errorf("%s must be a string", k)}
+		}
+		if _, _, err := parseClock(v, allow24); err != nil {
+			return nil, []error{fmt.Errorf("%s: %w", k, err)}
+		}
+		return nil, nil
+	}
+}
+
+func parseClock(s string, allow24 bool) (int, int, error) {
+	if allow24 && s == "24:00" {
+		return 24, 0, nil
+	}
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil {
+		if allow24 {
+			return 0, 0, fmt.Errorf("%q is not a time of day from 00:00 to 24:00", s)
+		}
+		return 0, 0, fmt.Errorf("%q is not a time of day from 00:00 to 23:59", s)
+	}
+	h, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi(m[2])
+	return h, min, nil
+}
+
+func formatClock(h, m int) string {
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
+
+// scheduleWindowsFromList converts `window` blocks to the API model. Blocks
+// whose times are not known yet are skipped.
+func scheduleWindowsFromList(raw []interface{}) []adaptive.ScheduleTimeWindow {
+	var out []adaptive.ScheduleTimeWindow
+	for _, v := range raw {
+		block, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sh, sm, err := parseClock(block["start"].(string), false)
+		if err != nil {
+			continue
+		}
+		eh, em, err := parseClock(block["end"].(string), true)
+		if err != nil {
+			continue
+		}
+		w := adaptive.ScheduleTimeWindow{StartHour: sh, StartMinute: sm, EndHour: eh, EndMinute: em}
+		for _, d := range block["weekdays"].([]interface{}) {
+			if s, ok := d.(string); ok && s != "" {
+				w.Weekdays = append(w.Weekdays, s)
+			}
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// flattenScheduleWindows converts windows read from the API to `window`
+// blocks.
+func flattenScheduleWindows(windows []adaptive.ScheduleTimeWindow) []interface{} {
+	out := make([]interface{}, 0, len(windows))
+	for _, w := range windows {
+		weekdays := make([]interface{}, 0, len(w.Weekdays))
+		for _, d := range w.Weekdays {
+			weekdays = append(weekdays, d)
+		}
+		out = append(out, map[string]interface{}{
+			"start":    formatClock(w.StartHour, w.StartMinute),
+			"end":      formatClock(w.EndHour, w.EndMinute),
+			"weekdays": weekdays,
+		})
+	}
+	return out
+}
+
+// scheduleTypeWeekdays are the weekdays the weekday-based schedule types
+// apply on. Other types can fall on any weekday.
+func scheduleTypeWeekdays(scheduleType string, custom []interface{}) []string {
+	switch scheduleType {
+	case "weekdays":
+		return weekdayNames[:5]
+	case "weekends":
+		return weekdayNames[5:]
+	case "custom":
+		var days []string
+		for _, d := range custom {
+			if s, ok := d.(string); ok {
+				days = append(days, s)
+			}
+		}
+		return days
+	}
+	return weekdayNames
+}
+
+const minutesPerWeek = 7 * 24 * 60
+
+type weeklyInterval struct {
+	window     int
+	day        string
+	start, end int
+}
+
+// checkScheduleWindows rejects zero-length windows, window weekdays the
+// schedule never applies on, and windows that overlap. Each window is laid
+// out on a weekly timeline, with overnight windows running into the next day
+// and Sunday night wrapping into Monday.
+func checkScheduleWindows(windows []adaptive.ScheduleTimeWindow, scheduleDays []string) error {
+	var intervals []weeklyInterval
+	for i, w := range windows {
+		start := w.StartHour*60 + w.StartMinute
+		end := w.EndHour*60 + w.EndMinute
+		if start == end {
+			return fmt.Errorf("window %d (%s-%s) is empty; use 00:00-24:00 for a whole day", i+1, formatClock(w.StartHour, w.StartMinute), formatClock(w.EndHour, w.EndMinute))
+		}
+		if end < start {
+			end += 24 * 60
+		}
+		days := w.Weekdays
+		if len(days) == 0 {
+			days = scheduleDays
+		}
+		seen := map[string]bool{}
+		for _, day := range days {
+			if seen[day] {
+				return fmt.Errorf("window %d lists %s more than once", i+1, day)
+			}
+			seen[day] = true
+			idx := indexOf(weekdayNames, day)
+			if indexOf(scheduleDays, day) < 0 {
+				return fmt.Errorf("window %d applies on %s, but the schedule never does; the schedule applies on %s", i+1, day, strings.Join(scheduleDays, ", "))
+			}
+			offset := idx * 24 * 60
+			intervals = append(intervals, weeklyInterval{window: i, day: day, start: offset + start, end: offset + end})
+			if offset+end > minutesPerWeek {
+				intervals = append(intervals, weeklyInterval{window: i, day: day, start: offset + start - minutesPerWeek, end: offset + end - minutesPerWeek})
+			}
+		}
+	}
+
+	sort.SliceStable(intervals, func(i, j int) bool { return intervals[i].start < intervals[j].start })
+	for i := range intervals {
+		for j := i + 1; j < len(intervals) && intervals[j].start < intervals[i].end; j++ {
+			a, b := intervals[i], intervals[j]
+			if a.window == b.window {
+				continue
+			}
+			if a.window > b.window {
+				a, b = b, a
+			}
+			wa, wb := windows[a.window], windows[b.window]
+			return fmt.Errorf("window %d (%s-%s on %s) overlaps window %d (%s-%s on %s)",
+				a.window+1, formatClock(wa.StartHour, wa.StartMinute), formatClock(wa.EndHour, wa.EndMinute), a.day,
+				b.window+1, formatClock(wb.StartHour, wb.StartMinute), formatClock(wb.EndHour, wb.EndMinute), b.day)
+		}
+	}
+	return nil
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,157 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// scheduleWindowsPatternFields are the adaptive_schedule attributes that
+// decide when a schedule applies. The data source takes them as inputs with
+// the resource's validation.
+var scheduleWindowsPatternFields = []string{
+	"schedule_type", "is_active", "all_day", "start_hour", "start_minute", "end_hour", "end_minute", "window",
+	"weekdays", "start_day", "end_day", "specific_dates", "rrule", "exclude_dates", "holiday_calendar", "timezone", "expires_at",
+}
+
+// DataSourceAdaptiveScheduleWindows evaluates a schedule pattern locally,
+// without calling the API, so the effect of an adaptive_schedule can be
+// checked before it is applied.
+func DataSourceAdaptiveScheduleWindows() *schema.Resource {
+	resourceSchema := ResourceAdaptiveSchedule().Schema
+	s := map[string]*schema.Schema{
+		"from": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.IsRFC3339Time,
+			Description:  "RFC3339 instant to list windows from. Defaults to the current time.",
+		},
+		"window_count": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      5,
+			ValidateFunc: validation.IntBetween(1, 500),
+			Description:  "Maximum number of windows to return.",
+		},
+		"is_open_at": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.IsRFC3339Time,
+			Description:  "RFC3339 instant to check. The answer is in `is_open`.",
+		},
+		"is_open": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the schedule applies at `is_open_at`. False when `is_open_at` is not set.",
+		},
+		"windows": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The next windows ending after `from`, in order, one per time-of-day window on each matching day. A window open at `from` is included with its actual start.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"start": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "RFC3339 start of the window, inclusive, in the schedule timezone.",
+					},
+					"end": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "RFC3339 end of the window, exclusive, in the schedule timezone.",
+					},
+				},
+			},
+		},
+	}
+	for _, k := range scheduleWindowsPatternFields {
+		field := *resourceSchema[k]
+		field.DiffSuppressFunc = nil
+		if e, ok := field.Elem.(*schema.Schema); ok {
+			elem := *e
+			elem.DiffSuppressFunc = nil
+			field.Elem = &elem
+		}
+		s[k] = &field
+	}
+	s["timezone"].Description = "IANA timezone the window is evaluated in. Defaults to UTC; unlike adaptive_schedule, the workspace default is not looked up."
+
+	return &schema.Resource{
+		Description: "Lists the windows of a schedule pattern, evaluated locally without calling the API.",
+		ReadContext: dataSourceAdaptiveScheduleWindowsRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceAdaptiveScheduleWindowsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	isActive := d.Get("is_active").(bool)
+	req := &adaptive.ScheduleRequest{
+		ScheduleType:  d.Get("schedule_type").(string),
+		IsActive:      &isActive,
+		AllDay:        d.Get("all_day").(bool),
+		StartHour:     d.Get("start_hour").(int),
+		StartMinute:   d.Get("start_minute").(int),
+		EndHour:       d.Get("end_hour").(int),
+		EndMinute:     d.Get("end_minute").(int),
+		StartDay:      d.Get("start_day").(int),
+		EndDay:        d.Get("end_day").(int),
+		Weekdays:      stringList(d, "weekdays"),
+		SpecificDates: stringList(d, "specific_dates"),
+		Windows:       scheduleWindowsFromList(d.Get("window").([]interface{})),
+		Timezone:      d.Get("timezone").(string),
+
+		RRule:           d.Get("rrule").(string),
+		ExcludeDates:    stringList(d, "exclude_dates"),
+		HolidayCalendar: d.Get("holiday_calendar").(string),
+	}
+	if v := d.Get("expires_at").(string); v != "" {
+		req.ExpiresAt = &v
+	}
+
+	from := time.Now()
+	if v := d.Get("from").(string); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("from: %w", err))
+		}
+		from = t
+	}
+
+	windows, err := req.NextWindows(from, d.Get("window_count").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	out := make([]interface{}, 0, len(windows))
+	for _, w := range windows {
+		out = append(out, map[string]interface{}{
+			"start": w.Start.Format(time.RFC3339),
+			"end":   w.End.Format(time.RFC3339),
+		})
+	}
+	if err := d.Set("windows", out); err != nil {
+		return diag.FromErr(err)
+	}
+
+	open := false
+	if v := d.Get("is_open_at").(string); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("is_open_at: %w", err))
+		}
+		if open, err = req.IsOpenAt(t); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("is_open", open); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.FormatInt(from.Unix(), 10))
+	return nil
+}
@@ -0,0 +1,40 @@
+package components
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceAdaptiveScheduleWindowsRead(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, DataSourceAdaptiveScheduleWindows().Schema, map[string]interface{}{
+		"schedule_type": "custom",
+		"weekdays":      []interface{}{"Monday", "Wednesday"},
+		"start_hour":    9,
+		"end_hour":      12,
+		"timezone":      "Europe/Berlin",
+		"from":          "2025-06-10T00:00:00Z",
+		"window_count":  2,
+		"is_open_at":    "2025-06-11T08:00:00Z",
+	})
+
+	if diags := dataSourceAdaptiveScheduleWindowsRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("read returned diagnostics: %+v", diags)
+	}
+
+	windows := d.Get("windows").([]interface{})
+	if len(windows) != 2 {
+		t.Fatalf("windows = %v, want 2", windows)
+	}
+	first := windows[0].(map[string]interface{})
+	if first["start"] != "2025-06-11T09:00:00+02:00" || first["end"] != "2025-06-11T12:00:00+02:00" {
+		t.Errorf("first window = %v", first)
+	}
+	if second := windows[1].(map[string]interface{}); second["start"] != "2025-06-16T09:00:00+02:00" {
+		t.Errorf("second window = %v", second)
+	}
+	if !d.Get("is_open").(bool) {
+		t.Error("expected the schedule to be open at 10:00 Berlin time on a Wednesday")
+	}
+}
@@ -31,7 +31,9 @@ func ResourceAdaptiveScript() *schema.Resource {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "",
-			}}}
+			},
+			"adopt_existing": adoptExistingSchema("script"),
+		}}
 }
 
 func ResourceAdaptiveScriptCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -52,12 +54,39 @@ func ResourceAdaptiveScriptCreate(ctx context.Context, d *schema.ResourceData, m
 
 	resp, err := client.CreateScript(ctx, *sName, *sCommand, *sEndpoint)
 	if err != nil {
+		if adaptive.IsDuplicateName(err) && shouldAdoptExisting(d, client) {
+			return adoptExistingScript(ctx, d, m, *sName, *sEndpoint, err)
+		}
 		return diag.FromErr(err)
 	}
 	d.SetId(resp.ID)
 
 	return nil
 }
+
+// adoptExistingScript takes over a script whose name collided on create. A
+// script's endpoint cannot change, so it must already match the configuration.
+func adoptExistingScript(ctx context.Context, d *schema.ResourceData, m interface{}, name, endpoint string, createErr error) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	existing, err := client.FindScriptByName(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if existing == nil {
+		return diag.FromErr(adoptNotFoundError("script", name, createErr))
+	}
+	if existing.Endpoint != endpoint {
+		return diag.Errorf("cannot adopt existing script %q: it belongs to endpoint %q but the configuration has endpoint %q", name, existing.Endpoint, endpoint)
+	}
+
+	d.SetId(existing.ID)
+	diags := ResourceAdaptiveScriptUpdate(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, adoptedWarning("script", name, existing.ID))
+}
 func ResourceAdaptiveScriptRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	return nil
 }
@@ -9,7 +9,6 @@ import (
 	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"golang.org/x/exp/slices"
 )
 
 const (
@@ -62,16 +61,6 @@ const (
 	EndpointMemory8192    = "8192Mi"
 )
 
-var validMemoryValues = []string{
-	EndpointMemory128,
-	EndpointMemory256,
-	EndpointMemory512,
-	EndpointMemory1024,
-	EndpointMemory2048,
-	EndpointMemory4096,
-	EndpointMemory8192,
-}
-
 var validIdleTimeoutValues = []string{
 	"15m",
 	"30m",
@@ -102,16 +91,6 @@ const (
 	EndpointCPU800     = "8"
 )
 
-var validCPUValues = []string{
-	EndpointCPU0125,
-	EndpointCPU025,
-	EndpointCPU050,
-	EndpointCPU100,
-	EndpointCPU200,
-	EndpointCPU400,
-	EndpointCPU800,
-}
-
 var validPauseTimeoutValues = []string{
 	"15m",
 	"30m",
@@ -137,6 +116,7 @@ func ResourceAdaptiveSession() *schema.Resource {
 		ReadContext:   ResourceAdaptiveSessionRead,
 		UpdateContext: ResourceAdaptiveSessionUpdate,
 		DeleteContext: ResourceAdaptiveSessionDelete,
+		CustomizeDiff: resourceAdaptiveSessionCustomizeDiff,
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
 			Update: schema.DefaultTimeout(10 * time.Minute),
@@ -153,30 +133,16 @@ func ResourceAdaptiveSession() *schema.Resource {
 				Required:    true,
 				Description: "The resource used to create the session.",
 			},
-			"type": {
-				Type:        schema.TypeString,
-				Default:     SessionTypeDefault,
-				Optional:    true,
-				Description: "The type of session to create.",
-			},
+			"type":             sessionTypeSchema(),
+			"services_options": servicesOptionsSchema(),
+			"script_options":   scriptOptionsSchema(),
 			"ttl": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "",
-				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
-					if _, ok := i.(string); !ok {
-						return nil, []error{fmt.Errorf("ttl must be a string")}
-					}
-
-					// make sure it's valid value
-					if !slices.Contains(
-						validTTLOptions, i.(string)) {
-						return nil, []error{fmt.Errorf("ttl must be one of %v", validTTLOptions)}
-					}
-
-					return nil, nil
-				},
-				Description: "The time-to-live (TTL) for the session. The session will be automatically terminated after this time period. If not set, defaults to 90 days.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateFunc:     ttlDuration.validate,
+				DiffSuppressFunc: suppressEquivalentDuration,
+				Description:      "The time-to-live (TTL) for the session. The session will be automatically terminated after this time period. Accepts durations such as 4h, 1h30m or 14d; values between the ones the backend supports are rounded down with a warning. If not set, defaults to 90 days.",
 			},
 			"authorization": {
 				Type:        schema.TypeString,
@@ -190,23 +156,13 @@ func ResourceAdaptiveSession() *schema.Resource {
 				Description: "The cluster in which this session should be created. If not provided will be set to default cluster set in workspace settings of the user's workspace",
 			},
 			"idle_timeout": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Description: "The time after which the session will be automatically terminated if no user is connected. Defaults to never timeout.",
-				ValidateFunc: func(i interface{}, k string) (ws []string, es []error) {
-					if _, ok := i.(string); !ok {
-						es = append(es, fmt.Errorf("idle_timeout must be a string"))
-					}
-
-					// make sure it's valid value
-					if !slices.Contains(
-						validIdleTimeoutValues, i.(string)) {
-						es = append(es, fmt.Errorf("idle_timeout must be one of %v", validIdleTimeoutValues))
-					}
-					return
-				},
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				Description:      "The time after which the session will be automatically terminated if no user is connected. Accepts durations such as 45m, 4h or 14d, or \"never\"; values between the ones the backend supports are rounded down with a warning. Defaults to never timeout.",
+				ValidateFunc:     idleTimeoutDuration.validate,
+				DiffSuppressFunc: suppressEquivalentDuration,
 			},
 			"users": {
 				Type:     schema.TypeList,
@@ -241,64 +197,49 @@ func ResourceAdaptiveSession() *schema.Resource {
 				},
 				Description: "The list of user emails who can approve Just-In-Time access requests",
 			},
+			"approval_policy": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"approval_policy_name", "jit_approvers"},
+				Description:   "Multi-stage approval of JIT access requests for this endpoint only. Replaces jit_approvers; requires is_jit_enabled.",
+				Elem:          &schema.Resource{Schema: approvalPolicySchema()},
+			},
+			"approval_policy_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"approval_policy", "jit_approvers"},
+				Description:   "Name of an adaptive_approval_policy that approves JIT access requests. Replaces jit_approvers; requires is_jit_enabled.",
+			},
 			"pause_timeout": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Description: "The time after which the session will be paused if no user has connected to it. Defaults to never pause.",
-				ValidateFunc: func(i interface{}, k string) (ws []string, es []error) {
-					if _, ok := i.(string); !ok {
-						es = append(es, fmt.Errorf("pause_timeout must be a string"))
-					}
-
-					// make sure it's valid value
-					if !slices.Contains(
-						validPauseTimeoutValues, i.(string)) {
-						es = append(es, fmt.Errorf("pause_timeout must be one of %v", validPauseTimeoutValues))
-					}
-
-					return
-				},
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				Description:      "The time after which the session will be paused if no user has connected to it. Accepts durations such as 45m, 4h or 14d, or \"never\"; values between the ones the backend supports are rounded down with a warning. Defaults to never pause.",
+				ValidateFunc:     pauseTimeoutDuration.validate,
+				DiffSuppressFunc: suppressEquivalentDuration,
 			},
 			"memory": {
-				Type:    schema.TypeString,
-				Default: "",
-				ValidateFunc: func(i interface{}, k string) (ws []string, es []error) {
-					if _, ok := i.(string); !ok {
-						es = append(es, fmt.Errorf("memory must be a string"))
-					}
-
-					// make sure it's valid value
-					if !slices.Contains(
-						validMemoryValues, i.(string)) {
-						es = append(es, fmt.Errorf("memory must be one of %v", validMemoryValues))
-					}
-
-					return
-				},
-				Optional:    true,
-				Description: "Memory of endpoint pod",
+				Type:             schema.TypeString,
+				Default:          "",
+				Optional:         true,
+				ValidateFunc:     validateQuantity,
+				DiffSuppressFunc: suppressEquivalentQuantity,
+				ConflictsWith:    []string{"requests", "limits"},
+				Description:      "Memory of endpoint pod, as a Kubernetes quantity such as 512Mi or 6Gi. A single value for the pod; use `requests` and `limits` to set them separately.",
 			},
 			"cpu": {
-				Type:    schema.TypeString,
-				Default: "",
-				ValidateFunc: func(i interface{}, k string) (ws []string, es []error) {
-					if _, ok := i.(string); !ok {
-						es = append(es, fmt.Errorf("cpu must be a string"))
-					}
-
-					// make sure it's valid value
-					if !slices.Contains(
-						validCPUValues, i.(string)) {
-						es = append(es, fmt.Errorf("cpu must be one of %v", validCPUValues))
-					}
-
-					return
-				},
-				Optional:    true,
-				Description: "CPU of endpoint pod",
+				Type:             schema.TypeString,
+				Default:          "",
+				Optional:         true,
+				ValidateFunc:     validateQuantity,
+				DiffSuppressFunc: suppressEquivalentQuantity,
+				ConflictsWith:    []string{"requests", "limits"},
+				Description:      "CPU of endpoint pod, as a Kubernetes quantity such as 500m or 3. A single value for the pod; use `requests` and `limits` to set them separately.",
 			},
+			"requests": endpointResourceBlockSchema("Resources reserved for the endpoint pod. Each value is checked against the provider's `endpoint_max_*` maximums and must not exceed the matching limit."),
+			"limits":   endpointResourceBlockSchema("Most resources the endpoint pod may use. Each value is checked against the provider's `endpoint_max_*` maximums."),
 			"script_only_access": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -313,6 +254,7 @@ func ResourceAdaptiveSession() *schema.Resource {
 				},
 				Description: "Optional tags",
 			},
+			"deletion_protection": deletionProtectionSchema("endpoint"),
 			"last_updated": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -323,34 +265,6 @@ func ResourceAdaptiveSession() *schema.Resource {
 	}
 }
 
-func isValidSessionType(t string) bool {
-	return t == SessionTypeDirect || t == SessionTypeClient || t == SessionTypeScript || t == SessionTypeCLI || t == SessionTypeServices
-}
-
-func getSessionType(t string) (string, bool) {
-	if t == "" {
-		return "cli", true
-	}
-
-	if isValidSessionType(t) {
-		switch t {
-		case SessionTypeDirect:
-			return "cli", true
-		case SessionTypeClient:
-			return "client", true
-		case SessionTypeCLI:
-			return "cli", true
-		case SessionTypeServices:
-			return "services", true
-		default:
-			return "", false
-		}
-	}
-
-	return "", false
-
-}
-
 func ResourceAdaptiveSessionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*adaptive.Client)
 
@@ -377,6 +291,10 @@ func ResourceAdaptiveSessionCreate(ctx context.Context, d *schema.ResourceData,
 	if !valid {
 		return diag.Errorf("Invalid session type: %s", sType)
 	}
+	unchecked, err := checkEndpointReferences(ctx, client, d, true, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	isJitEnabled := d.Get("is_jit_enabled")
 	if _, ok := isJitEnabled.(bool); !ok {
@@ -402,29 +320,26 @@ func ResourceAdaptiveSessionCreate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	pauseTimeout := d.Get("pause_timeout")
-	if _, ok := pauseTimeout.(string); !ok {
-		return diag.FromErr(fmt.Errorf("pause_timeout must be a string"))
+	ttl, err := ttlDuration.backendValue(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	userTags, err := integrations.TagsFromSchema(d)
+	pauseTimeout, err := pauseTimeoutDuration.backendValue(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	mem := d.Get("memory")
-	if _, ok := mem.(string); !ok {
-		return diag.FromErr(fmt.Errorf("memory must be a string"))
+	userTags, err := integrations.TagsFromSchema(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	cpu := d.Get("cpu")
-	if _, ok := cpu.(string); !ok {
-		return diag.FromErr(fmt.Errorf("cpu must be a string"))
-	}
+	resources, cpu, mem := endpointResourcesFromSchema(d)
 
-	idleTimeout := d.Get("idle_timeout")
-	if _, ok := idleTimeout.(string); !ok {
-		return diag.FromErr(fmt.Errorf("idle_timeout must be a string"))
+	idleTimeout, err := idleTimeoutDuration.backendValue(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	groups := d.Get("groups")
@@ -453,17 +368,20 @@ func ResourceAdaptiveSessionCreate(ctx context.Context, d *schema.ResourceData,
 		d.Get("resource").(string),
 		d.Get("authorization").(string),
 		d.Get("cluster").(string),
-		d.Get("ttl").(string),
+		ttl,
 		validSessionType,
 		isJitEnabled.(bool),
 		jitApproversEmails,
-		pauseTimeout.(string),
+		pauseTimeout,
 		userEmails,
-		mem.(string), cpu.(string),
+		mem, cpu,
 		userTags,
 		groupsVal,
-		idleTimeout.(string),
+		idleTimeout,
 		scriptOnlyAccess,
+		resources,
+		sessionTypeOptionsFromSchema(d),
+		endpointApprovalFromSchema(d),
 	)
 	if err != nil {
 		return diag.FromErr(err)
@@ -486,12 +404,24 @@ func ResourceAdaptiveSessionCreate(ctx context.Context, d *schema.ResourceData,
 	// }
 
 	ResourceAdaptiveSessionRead(ctx, d, m)
-	return nil
+	return uncheckedReferenceWarnings(d.Get("name").(string), unchecked)
 }
 
+// ResourceAdaptiveSessionRead refreshes the endpoint's approval policy, the
+// only setting the backend reports back; the rest of state is kept as applied.
 func ResourceAdaptiveSessionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
-	return diags
+	client := m.(*adaptive.Client)
+
+	approval, err := client.GetEndpointApproval(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if approval != nil {
+		if err := setEndpointApprovalState(d, approval); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
 }
 
 func ResourceAdaptiveSessionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -547,6 +477,13 @@ func ResourceAdaptiveSessionUpdate(ctx context.Context, d *schema.ResourceData,
 	if !valid {
 		return diag.Errorf("Invalid session type: %s", seshType)
 	}
+	var unchecked []string
+	if d.HasChanges("resource", "authorization", "type", "script_only_access") {
+		var err error
+		if unchecked, err = checkEndpointReferences(ctx, client, d, true, true); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
 	isJitEnabled := d.Get("is_jit_enabled")
 	if _, ok := isJitEnabled.(bool); !ok {
@@ -572,25 +509,22 @@ func ResourceAdaptiveSessionUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	pauseTimeout := d.Get("pause_timeout")
-	if _, ok := pauseTimeout.(string); !ok {
-		return diag.FromErr(fmt.Errorf("pause_timeout must be a string"))
+	ttl, err := ttlDuration.backendValue(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	idleTimeout := d.Get("idle_timeout")
-	if _, ok := idleTimeout.(string); !ok {
-		return diag.FromErr(fmt.Errorf("idle_timeout must be a string"))
+	pauseTimeout, err := pauseTimeoutDuration.backendValue(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	mem := d.Get("memory")
-	if _, ok := mem.(string); !ok {
-		return diag.FromErr(fmt.Errorf("memory must be a string"))
+	idleTimeout, err := idleTimeoutDuration.backendValue(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	cpu := d.Get("cpu")
-	if _, ok := cpu.(string); !ok {
-		return diag.FromErr(fmt.Errorf("cpu must be a string"))
-	}
+	resources, cpu, mem := endpointResourcesFromSchema(d)
 
 	userTags, err := integrations.TagsFromSchema(d)
 	if err != nil {
@@ -630,17 +564,20 @@ func ResourceAdaptiveSessionUpdate(ctx context.Context, d *schema.ResourceData,
 		d.Get("resource").(string),
 		authorizationName,
 		d.Get("cluster").(string),
-		d.Get("ttl").(string),
+		ttl,
 		validSessionType,
 		isJitEnabled.(bool),
 		jitApproversEmails,
-		pauseTimeout.(string),
+		pauseTimeout,
 		userEmails,
-		mem.(string), cpu.(string),
+		mem, cpu,
 		userTags,
 		groupsVal,
-		idleTimeout.(string),
+		idleTimeout,
 		scriptOnlyAccess,
+		resources,
+		sessionTypeOptionsFromSchema(d),
+		endpointApprovalFromSchema(d),
 	)
 	if err != nil {
 		return diag.FromErr(err)
@@ -648,12 +585,15 @@ func ResourceAdaptiveSessionUpdate(ctx context.Context, d *schema.ResourceData,
 
 	d.SetId(resp.ID)
 	ResourceAdaptiveSessionRead(ctx, d, m)
-	return nil
+	return uncheckedReferenceWarnings(d.Get("name").(string), unchecked)
 }
 
 func ResourceAdaptiveSessionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	sessionID := d.Id()
 	client := m.(*adaptive.Client)
+	if diags := checkDeletionProtection(d, client, "endpoint"); diags.HasError() {
+		return diags
+	}
 
 	_, err := client.DeleteSession(ctx, sessionID)
 	if err != nil {
@@ -0,0 +1,211 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adaptive-scale/terraform-provider-adaptive/internal/provider/integrations"
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// authorizationFamilies maps integration types to the authorization
+// resource_type family that governs them. An authorization applies to a
+// resource when both map to the same family.
+var authorizationFamilies = map[string]string{
+	"postgres":                      "postgres",
+	"postgres_aws_secrets_manager":  "postgres",
+	"mysql":                         "mysql",
+	"mysql_aws_secrets_manager":     "mysql",
+	"proxysql":                      "mysql",
+	"mongodb":                       "mongodb",
+	"mongodb_atlas":                 "mongodb",
+	"mongodb_aws_secrets_manager":   "mongodb",
+	"mongo36":                       "mongodb",
+	"sql_server":                    "sql_server",
+	"sqlserver_aws_secrets_manager": "sql_server",
+	"kubernetes":                    "kubernetes",
+	"ssh":                           "ssh",
+	"elasticsearch":                 "elasticsearch",
+	"yugabytedb":                    "yugabytedb",
+	"cockroachdb":                   "cockroachdb",
+}
+
+// scriptSupportedIntegrationTypes can run adaptive_script commands, and so
+// are the only types script endpoints and script_only_access make sense for.
+var scriptSupportedIntegrationTypes = map[string]bool{
+	"postgres":                      true,
+	"postgres_aws_secrets_manager":  true,
+	"mysql":                         true,
+	"mysql_aws_secrets_manager":     true,
+	"mongodb":                       true,
+	"mongodb_atlas":                 true,
+	"mongodb_aws_secrets_manager":   true,
+	"sql_server":                    true,
+	"sqlserver_aws_secrets_manager": true,
+	"cockroachdb":                   true,
+	"yugabytedb":                    true,
+	"clickhouse":                    true,
+	"snowflake":                     true,
+	"kubernetes":                    true,
+	"ssh":                           true,
+	"serverlist":                    true,
+}
+
+// checkEndpointConsistency verifies that an endpoint's resource, authorization
+// and session type fit together. An empty resourceType or authorizationType
+// means it is not known yet and the checks that need it are skipped.
+func checkEndpointConsistency(resourceType, authorizationType, sessionType string, scriptOnlyAccess bool) error {
+	var errs []error
+
+	if authorizationType != "" && !validAuthorizedResourceTypes[authorizationType] {
+		errs = append(errs, fmt.Errorf("authorization has resource_type %q, which is not a valid authorization type", authorizationType))
+	}
+
+	if resourceType != "" && authorizationType != "" {
+		resourceFamily, ok := authorizationFamilies[resourceType]
+		if !ok {
+			errs = append(errs, fmt.Errorf("resources of type %q do not support authorizations", resourceType))
+		} else if authorizationFamilies[authorizationType] != resourceFamily {
+			errs = append(errs, fmt.Errorf("authorization has resource_type %q, which cannot be applied to a %q resource", authorizationType, resourceType))
+		}
+	}
+
+	if sessionType == "" {
+		sessionType = SessionTypeDefault
+	}
+	if !isValidSessionType(sessionType) {
+		errs = append(errs, fmt.Errorf("endpoint type %q is not valid; valid types are: %s", sessionType, strings.Join(validSessionTypes(), ", ")))
+	} else if resourceType != "" {
+		switch sessionType {
+		case SessionTypeServices:
+			if resourceType != "services" {
+				errs = append(errs, fmt.Errorf("endpoint type %q is only valid for resources of type \"services\", not %q", sessionType, resourceType))
+			}
+		case SessionTypeScript:
+			if !scriptSupportedIntegrationTypes[resourceType] {
+				errs = append(errs, fmt.Errorf("endpoint type %q is not supported for resources of type %q", sessionType, resourceType))
+			}
+		default:
+			if resourceType == "services" {
+				errs = append(errs, fmt.Errorf("resources of type \"services\" are only reachable through endpoints of type %q, not %q", SessionTypeServices, sessionType))
+			}
+		}
+	}
+	if resourceType != "" {
+		if scriptOnlyAccess && !scriptSupportedIntegrationTypes[resourceType] {
+			errs = append(errs, fmt.Errorf("script_only_access is not supported for resources of type %q, which cannot run scripts", resourceType))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// resourceAdaptiveSessionCustomizeDiff checks the per-type options, the
+// approval policy and the pod resources against the provider's maximums,
+// then looks up the endpoint's resource and authorization by name and checks
+// they are consistent. A provider cannot read the planned values of other
+// resources, so references that do not exist yet because they are created in
+// the same apply are checked again by checkEndpointReferences at apply time.
+func resourceAdaptiveSessionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := customizeDiffSessionType(d); err != nil {
+		return err
+	}
+	if err := customizeDiffEndpointApproval(d); err != nil {
+		return err
+	}
+	if err := customizeDiffEndpointResources(d, m); err != nil {
+		return err
+	}
+
+	client, ok := m.(*adaptive.Client)
+	if !ok || client == nil {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChanges("resource", "authorization", "type", "script_only_access") {
+		return nil
+	}
+	if !d.NewValueKnown("type") || !d.NewValueKnown("script_only_access") {
+		return nil
+	}
+	// CustomizeDiff cannot warn; Create and Update report unchecked references.
+	_, err := checkEndpointReferences(ctx, client, d, d.NewValueKnown("resource"), d.NewValueKnown("authorization"))
+	return err
+}
+
+// checkEndpointReferences looks up the endpoint's resource and authorization
+// and runs checkEndpointConsistency on their types. Create and Update call it
+// again before writing, when references created in the same apply exist and
+// changed references carry their new types. A reference that is not known is
+// left out of the check, and so is one the lookup does not find, which is
+// returned in unchecked; a failed lookup is an error.
+func checkEndpointReferences(ctx context.Context, client *adaptive.Client, d integrations.SchemaGetter, resourceKnown, authorizationKnown bool) (unchecked []string, err error) {
+	resourceType := ""
+	if name, _ := d.Get("resource").(string); resourceKnown && name != "" {
+		t, err := lookupEndpointReference(ctx, name, "resource", client.FindResourceByName)
+		if err != nil {
+			return nil, err
+		}
+		if t == "" {
+			unchecked = append(unchecked, fmt.Sprintf("resource %q", name))
+		}
+		resourceType = t
+		if resourceType == "servicelist" {
+			resourceType = "services"
+		}
+	}
+
+	authorizationType := ""
+	if name, _ := d.Get("authorization").(string); authorizationKnown && name != "" {
+		t, err := lookupEndpointReference(ctx, name, "authorization", client.FindAuthorizationByName)
+		if err != nil {
+			return nil, err
+		}
+		if t == "" {
+			unchecked = append(unchecked, fmt.Sprintf("authorization %q", name))
+		}
+		authorizationType = t
+	}
+
+	sessionType, _ := d.Get("type").(string)
+	scriptOnlyAccess, _ := d.Get("script_only_access").(bool)
+	return unchecked, checkEndpointConsistency(resourceType, authorizationType, sessionType, scriptOnlyAccess)
+}
+
+// uncheckedReferenceWarnings tells the user which references were left out of
+// the consistency check. The lookup uses GET {api}/find/{name}, which current
+// backends do not serve, so there a 404 means the check could not run at all.
+func uncheckedReferenceWarnings(name string, unchecked []string) diag.Diagnostics {
+	if len(unchecked) == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Endpoint %q was not checked against its %s", name, strings.Join(unchecked, " and ")),
+		Detail:   "The provider could not look them up by name, so the resource, authorization and type consistency checks did not run for them. The lookup needs a backend that serves GET /terraform/<kind>/find/<name>; backends without it return 404 for every name.",
+	}}
+}
+
+// lookupEndpointReference returns the type of the named object, or "" if the
+// lookup finds no object with the name.
+func lookupEndpointReference(ctx context.Context, name, kind string, find func(context.Context, string) (*adaptive.NamedObject, error)) (string, error) {
+	obj, err := find(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("could not look up %s %q to check the endpoint against it: %w", kind, name, err)
+	}
+	if obj == nil {
+		tflog.Debug(ctx, "Endpoint reference not found, skipping its consistency check", map[string]interface{}{
+			"kind": kind,
+			"name": name,
+		})
+		return "", nil
+	}
+	if kind == "authorization" {
+		return strings.TrimSpace(obj.ResourceType), nil
+	}
+	return strings.TrimSpace(obj.IntegrationType), nil
+}
@@ -0,0 +1,97 @@
+package components
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckEndpointConsistency(t *testing.T) {
+	tests := []struct {
+		name              string
+		resourceType      string
+		authorizationType string
+		sessionType       string
+		scriptOnlyAccess  bool
+		wantErr           bool
+	}{
+		{name: "matching types", resourceType: "postgres", authorizationType: "postgres", sessionType: "direct"},
+		{name: "same family", resourceType: "postgres_aws_secrets_manager", authorizationType: "postgres", sessionType: "direct"},
+		{name: "mismatched types", resourceType: "kubernetes", authorizationType: "postgres", sessionType: "direct", wantErr: true},
+		{name: "invalid authorization type", resourceType: "postgres", authorizationType: "redis", sessionType: "direct", wantErr: true},
+		{name: "resource without authorization support", resourceType: "redis", authorizationType: "postgres", sessionType: "direct", wantErr: true},
+		{name: "unknown resource", authorizationType: "postgres", sessionType: "direct"},
+		{name: "unknown authorization", resourceType: "kubernetes", sessionType: "direct"},
+		{name: "services session on services", resourceType: "services", sessionType: "services"},
+		{name: "services session on postgres", resourceType: "postgres", sessionType: "services", wantErr: true},
+		{name: "script session on ssh", resourceType: "ssh", sessionType: "script"},
+		{name: "script session on redis", resourceType: "redis", sessionType: "script", wantErr: true},
+		{name: "cli session on services", resourceType: "services", sessionType: "cli", wantErr: true},
+		{name: "default session on services", resourceType: "services", wantErr: true},
+		{name: "client session on postgres", resourceType: "postgres", sessionType: "client"},
+		{name: "unknown session type", sessionType: "ssh", wantErr: true},
+		{name: "script only access on mysql", resourceType: "mysql", sessionType: "direct", scriptOnlyAccess: true},
+		{name: "script only access on aws", resourceType: "aws", sessionType: "direct", scriptOnlyAccess: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkEndpointConsistency(tt.resourceType, tt.authorizationType, tt.sessionType, tt.scriptOnlyAccess)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkEndpointConsistency() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// A failed lookup must surface instead of skipping the check, and a
+// reference the lookup does not find, as on backends without the find route,
+// is reported as unchecked so Create and Update can warn about it.
+func TestCheckEndpointReferences(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/terraform/resource/find/cluster"):
+			_, _ = w.Write([]byte(`{"id": "res-1", "name": "cluster", "integrationType": "kubernetes"}`))
+		case strings.HasSuffix(r.URL.Path, "/terraform/authorization/find/pg-ro"):
+			_, _ = w.Write([]byte(`{"id": "auth-1", "name": "pg-ro", "resourceType": "postgres"}`))
+		case strings.HasSuffix(r.URL.Path, "/terraform/authorization/find/broken"):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := adaptive.NewClient("test-token", srv.URL)
+
+	for _, tt := range []struct {
+		name, resource, authorization, wantErr, wantUnchecked string
+	}{
+		{name: "mismatched types", resource: "cluster", authorization: "pg-ro", wantErr: `cannot be applied to a "kubernetes" resource`},
+		{name: "lookup failure", resource: "cluster", authorization: "broken", wantErr: `could not look up authorization "broken"`},
+		{name: "not found", resource: "new-db", authorization: "pg-ro", wantUnchecked: `resource "new-db"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, ResourceAdaptiveSession().Schema, map[string]interface{}{
+				"name": "e", "resource": tt.resource, "authorization": tt.authorization,
+			})
+			unchecked, err := checkEndpointReferences(context.Background(), client, d, true, true)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := strings.Join(unchecked, ", "); got != tt.wantUnchecked {
+					t.Errorf("unchecked = %q, want %q", got, tt.wantUnchecked)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
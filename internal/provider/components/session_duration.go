@@ -0,0 +1,184 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// durationNever is the explicit "no timeout" value. The backend encodes it as
+// durationNeverBackend, which is also accepted as a legacy alias.
+const (
+	durationNever        = "never"
+	durationNeverBackend = "99999d"
+)
+
+const day = 24 * time.Hour
+
+var dayDurationPattern = regexp.MustCompile(`^(\d+)d(.*)$`)
+
+// endpointDuration describes an adaptive_endpoint duration attribute and the
+// values the backend accepts for it.
+type endpointDuration struct {
+	attr       string
+	supported  []string
+	allowNever bool
+}
+
+var (
+	ttlDuration          = endpointDuration{attr: "ttl", supported: validTTLOptions}
+	idleTimeoutDuration  = endpointDuration{attr: "idle_timeout", supported: validIdleTimeoutValues, allowNever: true}
+	pauseTimeoutDuration = endpointDuration{attr: "pause_timeout", supported: validPauseTimeoutValues, allowNever: true}
+)
+
+// parseEndpointDuration parses a Go duration such as "90m" or "1h30m", a day
+// count such as "14d" optionally followed by a Go duration ("1d12h"), or
+// "never". never is true for "never" and its legacy spelling "99999d".
+func parseEndpointDuration(s string) (d time.Duration, never bool, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == durationNever || s == durationNeverBackend {
+		return 0, true, nil
+	}
+	rest := s
+	if m := dayDurationPattern.FindStringSubmatch(s); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil || days > 36500 {
+			return 0, false, fmt.Errorf("%q has too many days", s)
+		}
+		d, rest = time.Duration(days)*day, m[2]
+	}
+	if rest != "" {
+		extra, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, false, fmt.Errorf("%q is not a duration such as 30m, 4h, 1h30m or 14d", s)
+		}
+		d += extra
+	}
+	if d <= 0 {
+		return 0, false, fmt.Errorf("%q must be a positive duration", s)
+	}
+	return d, false, nil
+}
+
+// formatEndpointDuration renders d in the backend's notation: whole days as
+// "Nd", whole hours as "Nh", and minutes otherwise.
+func formatEndpointDuration(d time.Duration) string {
+	switch {
+	case d%day == 0:
+		return fmt.Sprintf("%dd", d/day)
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return fmt.Sprintf("%dm", d/time.Minute)
+}
+
+// supportedDurations returns the finite values the backend accepts, shortest
+// first.
+func (e endpointDuration) supportedDurations() []time.Duration {
+	var out []time.Duration
+	for _, s := range e.supported {
+		if d, never, err := parseEndpointDuration(s); s != "" && err == nil && !never {
+			out = append(out, d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// normalize maps a configured value to the value sent to the backend. A
+// duration between two supported values is rounded down, so access never
+// lasts longer than configured, and the returned warning says so. Values
+// shorter than the shortest or longer than the longest supported value are
+// rejected.
+func (e endpointDuration) normalize(s string) (value, warning string, err error) {
+	if s == "" {
+		return "", "", nil
+	}
+	d, never, err := parseEndpointDuration(s)
+	if err != nil {
+		return "", "", fmt.Errorf("%s: %w", e.attr, err)
+	}
+	supported := e.supportedDurations()
+	longest := formatEndpointDuration(supported[len(supported)-1])
+	if never {
+		if !e.allowNever {
+			return "", "", fmt.Errorf("%s cannot be %q; the longest supported %s is %s", e.attr, s, e.attr, longest)
+		}
+		return durationNeverBackend, "", nil
+	}
+
+	if d < supported[0] {
+		return "", "", fmt.Errorf("%s %q is shorter than the shortest supported %s, %s", e.attr, s, e.attr, formatEndpointDuration(supported[0]))
+	}
+	if d > supported[len(supported)-1] {
+		hint := ""
+		if e.allowNever {
+			hint = fmt.Sprintf(`; use %q for no timeout`, durationNever)
+		}
+		return "", "", fmt.Errorf("%s %q is longer than the longest supported %s, %s%s", e.attr, s, e.attr, longest, hint)
+	}
+	i := sort.Search(len(supported), func(i int) bool { return supported[i] > d }) - 1
+	value = formatEndpointDuration(supported[i])
+	if supported[i] != d {
+		warning = fmt.Sprintf("%s %q is not a value the backend supports; it is rounded down to %s. Supported values: %s", e.attr, s, value, e.supportedList())
+	}
+	return value, warning, nil
+}
+
+func (e endpointDuration) supportedList() string {
+	var names []string
+	for _, d := range e.supportedDurations() {
+		names = append(names, formatEndpointDuration(d))
+	}
+	if e.allowNever {
+		names = append(names, durationNever)
+	}
+	return strings.Join(names, ", ")
+}
+
+// validate is the attribute's ValidateFunc. Rounding is reported as a warning.
+func (e endpointDuration) validate(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s must be a string", k)}
+	}
+	_, warning, err := e.normalize(v)
+	if err != nil {
+		return nil, []error{err}
+	}
+	if warning != "" {
+		return []string{warning}, nil
+	}
+	return nil, nil
+}
+
+// backendValue returns the normalized value of the attribute for the API.
+func (e endpointDuration) backendValue(d *schema.ResourceData) (string, error) {
+	v, _, err := e.normalize(d.Get(e.attr).(string))
+	return v, err
+}
+
+// suppressEquivalentDuration ignores differences between spellings of the
+// same duration, such as "24h" and "1d", or "never" and "99999d".
+func suppressEquivalentDuration(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	if old == "" || new == "" {
+		return false
+	}
+	od, oNever, err := parseEndpointDuration(old)
+	if err != nil {
+		return false
+	}
+	nd, nNever, err := parseEndpointDuration(new)
+	if err != nil {
+		return false
+	}
+	return oNever == nNever && od == nd
+}
@@ -0,0 +1,98 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestEndpointDurationNormalize(t *testing.T) {
+	tests := []struct {
+		setting endpointDuration
+		in      string
+		want    string
+		warns   bool
+		wantErr string
+	}{
+		{setting: ttlDuration, in: "", want: ""},
+		{setting: ttlDuration, in: "6h", want: "6h"},
+		{setting: ttlDuration, in: "24h", want: "1d"},
+		{setting: ttlDuration, in: "168h", want: "7d"},
+		{setting: ttlDuration, in: "4h", want: "3h", warns: true},
+		{setting: ttlDuration, in: "14d", want: "7d", warns: true},
+		{setting: ttlDuration, in: "1d12h", want: "1d", warns: true},
+		{setting: ttlDuration, in: "2h", wantErr: "shorter than the shortest supported ttl, 3h"},
+		{setting: ttlDuration, in: "400d", wantErr: "longest supported ttl, 365d"},
+		{setting: ttlDuration, in: "never", wantErr: "cannot be"},
+		{setting: ttlDuration, in: "fortnight", wantErr: "not a duration"},
+		{setting: ttlDuration, in: "0h", wantErr: "positive"},
+		{setting: idleTimeoutDuration, in: "never", want: "99999d"},
+		{setting: idleTimeoutDuration, in: "99999d", want: "99999d"},
+		{setting: idleTimeoutDuration, in: "90m", want: "1h", warns: true},
+		{setting: idleTimeoutDuration, in: "60m", want: "1h"},
+		{setting: idleTimeoutDuration, in: "5m", wantErr: "shortest supported idle_timeout, 15m"},
+		{setting: pauseTimeoutDuration, in: "1000d", wantErr: `use "never"`},
+		{setting: pauseTimeoutDuration, in: "45m", want: "30m", warns: true},
+	}
+	for _, tt := range tests {
+		got, warning, err := tt.setting.normalize(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s %q: error = %v, want one containing %q", tt.setting.attr, tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", tt.setting.attr, tt.in, err)
+			continue
+		}
+		if got != tt.want || (warning != "") != tt.warns {
+			t.Errorf("%s %q = %q (warning %q), want %q (warns %v)", tt.setting.attr, tt.in, got, warning, tt.want, tt.warns)
+		}
+	}
+}
+
+func TestSuppressEquivalentDuration(t *testing.T) {
+	for _, tt := range []struct {
+		old, new string
+		want     bool
+	}{
+		{"24h", "1d", true},
+		{"1d12h", "36h", true},
+		{"never", "99999d", true},
+		{"90m", "1h30m", true},
+		{"4h", "3h", false},
+		{"", "1d", false},
+		{"never", "365d", false},
+	} {
+		if got := suppressEquivalentDuration("ttl", tt.old, tt.new, nil); got != tt.want {
+			t.Errorf("suppress(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestResourceAdaptiveSessionDurationValidation(t *testing.T) {
+	res := ResourceAdaptiveSession()
+	diags := res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":         "e",
+		"resource":     "r",
+		"ttl":          "4h",
+		"idle_timeout": "never",
+	}))
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+	if len(diags) != 1 || !strings.Contains(diags[0].Summary+diags[0].Detail, "rounded down to 3h") {
+		t.Errorf("expected a rounding warning, got %+v", diags)
+	}
+
+	diags = res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":          "e",
+		"resource":      "r",
+		"pause_timeout": "5m",
+	}))
+	if !diags.HasError() {
+		t.Error("expected a pause_timeout below the shortest supported value to be rejected")
+	}
+}
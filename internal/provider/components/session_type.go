@@ -0,0 +1,194 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/adaptive-scale/terraform-provider-adaptive/internal/provider/integrations"
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// sessionTypeBackendValues maps each accepted endpoint type to the session
+// type the API expects. direct is the historical name of cli; both send
+// "cli", and state keeps whichever spelling is configured.
+var sessionTypeBackendValues = map[string]string{
+	SessionTypeDirect:   "cli",
+	SessionTypeCLI:      "cli",
+	SessionTypeClient:   "client",
+	SessionTypeScript:   "script",
+	SessionTypeServices: "services",
+}
+
+// sessionTypeOptionBlocks maps each per-type options block to the only
+// endpoint type it applies to.
+var sessionTypeOptionBlocks = map[string]string{
+	"services_options": SessionTypeServices,
+	"script_options":   SessionTypeScript,
+}
+
+// validSessionTypes returns the accepted endpoint types, sorted.
+func validSessionTypes() []string {
+	var types []string
+	for t := range sessionTypeBackendValues {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func isValidSessionType(t string) bool {
+	_, ok := sessionTypeBackendValues[t]
+	return ok
+}
+
+// getSessionType returns the API session type for an endpoint type. An
+// empty type is the default, direct.
+func getSessionType(t string) (string, bool) {
+	if t == "" {
+		t = SessionTypeDefault
+	}
+	v, ok := sessionTypeBackendValues[t]
+	return v, ok
+}
+
+// suppressEquivalentSessionType ignores changes between aliases of the same
+// session type, such as direct and cli.
+func suppressEquivalentSessionType(k, old, new string, d *schema.ResourceData) bool {
+	o, ok := getSessionType(old)
+	if !ok {
+		return false
+	}
+	n, ok := getSessionType(new)
+	return ok && o == n
+}
+
+func sessionTypeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Default:          SessionTypeDefault,
+		Optional:         true,
+		ValidateFunc:     validation.StringInSlice(validSessionTypes(), false),
+		DiffSuppressFunc: suppressEquivalentSessionType,
+		Description:      "The type of session to create. One of: cli, client, direct, script, services. direct is an alias of cli; changing between them does not produce a diff.",
+	}
+}
+
+func servicesOptionsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Options for type = services.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"ports": {
+					Type:        schema.TypeList,
+					Required:    true,
+					MinItems:    1,
+					Elem:        &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IsPortNumber},
+					Description: "Ports of the services resource exposed through the endpoint. Each port may be listed once.",
+				},
+			},
+		},
+	}
+}
+
+func scriptOptionsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Options for type = script.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"allowed_scripts": {
+					Type:        schema.TypeList,
+					Required:    true,
+					MinItems:    1,
+					Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotEmpty},
+					Description: "Names of the adaptive_script scripts the endpoint may run. Each script may be listed once.",
+				},
+			},
+		},
+	}
+}
+
+// sessionTypeOptionsFromSchema reads the options block of the endpoint's
+// type. Blocks for other types are rejected at plan time and ignored here.
+func sessionTypeOptionsFromSchema(d integrations.SchemaGetter) adaptive.SessionTypeOptions {
+	var opts adaptive.SessionTypeOptions
+	sessionType, _ := d.Get("type").(string)
+	switch sessionType {
+	case SessionTypeServices:
+		if block := firstBlock(d.Get("services_options")); block != nil {
+			for _, p := range block["ports"].([]interface{}) {
+				opts.ExposedPorts = append(opts.ExposedPorts, p.(int))
+			}
+		}
+	case SessionTypeScript:
+		if block := firstBlock(d.Get("script_options")); block != nil {
+			for _, s := range block["allowed_scripts"].([]interface{}) {
+				name, _ := s.(string)
+				opts.AllowedScripts = append(opts.AllowedScripts, name)
+			}
+		}
+	}
+	return opts
+}
+
+func firstBlock(raw interface{}) map[string]interface{} {
+	list, _ := raw.([]interface{})
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	return list[0].(map[string]interface{})
+}
+
+// checkSessionTypeOptions checks that only the options block of the
+// endpoint's type is set, and that its lists have no duplicates.
+func checkSessionTypeOptions(sessionType string, blocks map[string]bool, opts adaptive.SessionTypeOptions) error {
+	var errs []error
+	names := make([]string, 0, len(sessionTypeOptionBlocks))
+	for name := range sessionTypeOptionBlocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if blocks[name] && sessionTypeOptionBlocks[name] != sessionType {
+			errs = append(errs, fmt.Errorf("%s is only valid with type = %q, not %q", name, sessionTypeOptionBlocks[name], sessionType))
+		}
+	}
+
+	seenPorts := map[int]bool{}
+	for _, p := range opts.ExposedPorts {
+		if seenPorts[p] {
+			errs = append(errs, fmt.Errorf("services_options.ports lists port %d more than once", p))
+		}
+		seenPorts[p] = true
+	}
+	seenScripts := map[string]bool{}
+	for _, s := range opts.AllowedScripts {
+		if seenScripts[s] {
+			errs = append(errs, fmt.Errorf("script_options.allowed_scripts lists %q more than once", s))
+		}
+		seenScripts[s] = true
+	}
+	return errors.Join(errs...)
+}
+
+// customizeDiffSessionType enforces checkSessionTypeOptions at plan time,
+// once the type and the options blocks are known.
+func customizeDiffSessionType(d *schema.ResourceDiff) error {
+	if !newValuesKnown(d, "type", "services_options", "script_options") {
+		return nil
+	}
+	sessionType := d.Get("type").(string)
+	blocks := map[string]bool{}
+	for name := range sessionTypeOptionBlocks {
+		blocks[name] = firstBlock(d.Get(name)) != nil
+	}
+	return checkSessionTypeOptions(sessionType, blocks, sessionTypeOptionsFromSchema(d))
+}
@@ -0,0 +1,145 @@
+package components
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestGetSessionType(t *testing.T) {
+	for in, want := range map[string]string{
+		"":         "cli",
+		"direct":   "cli",
+		"cli":      "cli",
+		"client":   "client",
+		"script":   "script",
+		"services": "services",
+	} {
+		got, ok := getSessionType(in)
+		if !ok || got != want {
+			t.Errorf("getSessionType(%q) = %q, %v, want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := getSessionType("ssh"); ok {
+		t.Error("expected an unknown session type to be rejected")
+	}
+}
+
+func TestSuppressEquivalentSessionType(t *testing.T) {
+	for _, tt := range []struct {
+		old, new string
+		want     bool
+	}{
+		{"direct", "cli", true},
+		{"", "direct", true},
+		{"cli", "client", false},
+		{"script", "services", false},
+		{"direct", "bogus", false},
+	} {
+		if got := suppressEquivalentSessionType("type", tt.old, tt.new, nil); got != tt.want {
+			t.Errorf("suppress(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestResourceAdaptiveSessionTypeValidation(t *testing.T) {
+	res := ResourceAdaptiveSession()
+	diags := res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "e", "resource": "r", "type": "ssh",
+	}))
+	if !diags.HasError() {
+		t.Error("expected an unknown type to be rejected at plan time")
+	}
+	diags = res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "e", "resource": "r", "type": "services",
+		"services_options": []interface{}{map[string]interface{}{"ports": []interface{}{8080, 70000}}},
+	}))
+	if !diags.HasError() {
+		t.Error("expected an out-of-range port to be rejected")
+	}
+}
+
+func TestSessionTypeOptionsFromSchema(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveSession().Schema, map[string]interface{}{
+		"name": "e", "resource": "r", "type": "script",
+		"script_options": []interface{}{map[string]interface{}{"allowed_scripts": []interface{}{"vacuum", "reindex"}}},
+	})
+	opts := sessionTypeOptionsFromSchema(d)
+	if !reflect.DeepEqual(opts.AllowedScripts, []string{"vacuum", "reindex"}) || opts.ExposedPorts != nil {
+		t.Errorf("options = %+v", opts)
+	}
+}
+
+func TestResourceAdaptiveSessionCustomizeDiff_TypeOptions(t *testing.T) {
+	res := ResourceAdaptiveSession()
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{name: "services ports", config: map[string]interface{}{
+			"type":             "services",
+			"services_options": []interface{}{map[string]interface{}{"ports": []interface{}{8080, 9090}}},
+		}},
+		{name: "script allowlist", config: map[string]interface{}{
+			"type":           "script",
+			"script_options": []interface{}{map[string]interface{}{"allowed_scripts": []interface{}{"vacuum"}}},
+		}},
+		{name: "options of another type", config: map[string]interface{}{
+			"type":             "direct",
+			"services_options": []interface{}{map[string]interface{}{"ports": []interface{}{8080}}},
+		}, wantErr: `services_options is only valid with type = "services", not "direct"`},
+		{name: "duplicate port", config: map[string]interface{}{
+			"type":             "services",
+			"services_options": []interface{}{map[string]interface{}{"ports": []interface{}{8080, 8080}}},
+		}, wantErr: "port 8080 more than once"},
+		{name: "duplicate script", config: map[string]interface{}{
+			"type":           "script",
+			"script_options": []interface{}{map[string]interface{}{"allowed_scripts": []interface{}{"vacuum", "vacuum"}}},
+		}, wantErr: `"vacuum" more than once`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["name"] = "e"
+			tt.config["resource"] = "r"
+			_, err := res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceAdaptiveSessionTypeAliasNoDiff(t *testing.T) {
+	res := ResourceAdaptiveSession()
+	state := &terraform.InstanceState{
+		ID: "abc",
+		Attributes: map[string]string{
+			"id": "abc", "name": "e", "resource": "r", "type": "direct",
+			"script_only_access": "false", "is_jit_enabled": "false",
+			"cpu": "", "memory": "", "ttl": "", "idle_timeout": "", "pause_timeout": "", "cluster": "",
+			"deletion_protection": "false",
+		},
+	}
+	diff, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "e", "resource": "r", "type": "cli",
+	}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil {
+		if attr, ok := diff.Attributes["type"]; ok {
+			t.Errorf("expected no diff between direct and cli, got %+v", attr)
+		}
+	}
+}
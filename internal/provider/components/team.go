@@ -29,7 +29,7 @@ func ResourceAdaptiveTeam() *schema.Resource {
 					Type: schema.TypeString,
 				},
 				Optional:    true,
-				Description: "List of emails to add to the team. If empty, the group will be created without members. ",
+				Description: "List of emails to add to the team. If empty, the group will be created without members. Authoritative: when changed, replaces every member of the group, including those added by `adaptive_group_member`.",
 			},
 			"endpoints": {
 				Type: schema.TypeList,
@@ -37,8 +37,10 @@ func ResourceAdaptiveTeam() *schema.Resource {
 					Type: schema.TypeString,
 				},
 				Optional:    true,
-				Description: "List of names of endpoints to add to this group. If empty, the group will be created without endpoints.",
+				Description: "List of names of endpoints to add to this group. If empty, the group will be created without endpoints. Authoritative: when changed, replaces every endpoint of the group, including those added by `adaptive_group_endpoint`.",
 			},
+			"deletion_protection": deletionProtectionSchema("group"),
+			"adopt_existing":      adoptExistingSchema("group"),
 		},
 	}
 }
@@ -79,6 +81,9 @@ func ResourceAdaptiveTeamCreate(ctx context.Context, d *schema.ResourceData, m i
 
 	resp, err := client.CreateTeam(ctx, name, &members, &endpoints)
 	if err != nil {
+		if adaptive.IsDuplicateName(err) && shouldAdoptExisting(d, client) {
+			return adoptExistingTeam(ctx, d, m, *name, members, endpoints, err)
+		}
 		return diag.FromErr(err)
 	}
 	d.SetId(resp.ID)
@@ -86,6 +91,28 @@ func ResourceAdaptiveTeamCreate(ctx context.Context, d *schema.ResourceData, m i
 	return nil
 }
 
+// adoptExistingTeam takes over a group whose name collided on create and
+// replaces its members and endpoints with the configured ones. Both lists
+// are always sent: update only sends changed lists, and on create nothing
+// has changed yet, so the group would keep members it had before adoption.
+func adoptExistingTeam(ctx context.Context, d *schema.ResourceData, m interface{}, name string, members, endpoints []string, createErr error) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	existing, err := client.FindTeamByName(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if existing == nil {
+		return diag.FromErr(adoptNotFoundError("group", name, createErr))
+	}
+
+	if _, err := client.UpdateTeam(ctx, &existing.ID, &name, &members, &endpoints); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(existing.ID)
+	return diag.Diagnostics{adoptedWarning("group", name, existing.ID)}
+}
+
 func ResourceAdaptiveTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// client := m.(*adaptive.Client)
 
@@ -139,7 +166,17 @@ func ResourceAdaptiveTeamUpdate(ctx context.Context, d *schema.ResourceData, m i
 		}
 	}
 
-	if _, err := client.UpdateTeam(ctx, &teamID, name, &members, &endpoints); err != nil {
+	// Only send the lists that changed, so members and endpoints added by
+	// adaptive_group_member and adaptive_group_endpoint survive a rename.
+	var membersArg, endpointsArg *[]string
+	if d.HasChange("members") {
+		membersArg = &members
+	}
+	if d.HasChange("endpoints") {
+		endpointsArg = &endpoints
+	}
+
+	if _, err := client.UpdateTeam(ctx, &teamID, name, membersArg, endpointsArg); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -149,6 +186,9 @@ func ResourceAdaptiveTeamUpdate(ctx context.Context, d *schema.ResourceData, m i
 func ResourceAdaptiveTeamDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*adaptive.Client)
 	teamID := d.Id()
+	if diags := checkDeletionProtection(d, client, "group"); diags.HasError() {
+		return diags
+	}
 
 	if _, err := client.DeleteTeam(ctx, teamID, d.Get("name").(string)); err != nil {
 		return diag.FromErr(err)
@@ -0,0 +1,180 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/exp/slices"
+)
+
+// adaptive_group_member and adaptive_group_endpoint each manage one entry of a
+// group's members or endpoints, leaving the rest of the group alone. Their IDs
+// are "<group>/<email>" and "<group>/<endpoint>".
+
+func ResourceAdaptiveTeamMember() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: ResourceAdaptiveTeamMemberCreate,
+		ReadContext:   ResourceAdaptiveTeamMemberRead,
+		DeleteContext: ResourceAdaptiveTeamMemberDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the group to add the user to.",
+			},
+			"email": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Email of the user to add to the group.",
+			},
+		},
+	}
+}
+
+func ResourceAdaptiveTeamEndpoint() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: ResourceAdaptiveTeamEndpointCreate,
+		ReadContext:   ResourceAdaptiveTeamEndpointRead,
+		DeleteContext: ResourceAdaptiveTeamEndpointDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the group to grant access to.",
+			},
+			"endpoint": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the endpoint to add to the group.",
+			},
+		},
+	}
+}
+
+func teamMembershipID(group, item string) string {
+	return group + "/" + item
+}
+
+// parseTeamMembershipID splits on the last "/", since group names may contain
+// "/" but emails and endpoint names do not.
+func parseTeamMembershipID(id, itemKind string) (group, item string, err error) {
+	i := strings.LastIndex(id, "/")
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid ID %q, expected <group>/<%s>", id, itemKind)
+	}
+	group, item = id[:i], id[i+1:]
+	if group == "" || item == "" {
+		return "", "", fmt.Errorf("invalid ID %q, expected <group>/<%s>", id, itemKind)
+	}
+	return group, item, nil
+}
+
+func ResourceAdaptiveTeamMemberCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+	group := d.Get("group").(string)
+	email := d.Get("email").(string)
+
+	if err := client.AddTeamMember(ctx, group, email); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(teamMembershipID(group, email))
+	return nil
+}
+
+func ResourceAdaptiveTeamMemberRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+	group, email, err := parseTeamMembershipID(d.Id(), "email")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	membership, err := client.GetTeamMembership(ctx, group)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if membership == nil || !slices.Contains(membership.Members, email) {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("group", group); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("email", email); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func ResourceAdaptiveTeamMemberDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	if err := client.RemoveTeamMember(ctx, d.Get("group").(string), d.Get("email").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return nil
+}
+
+func ResourceAdaptiveTeamEndpointCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+	group := d.Get("group").(string)
+	endpoint := d.Get("endpoint").(string)
+
+	if err := client.AddTeamEndpoint(ctx, group, endpoint); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(teamMembershipID(group, endpoint))
+	return nil
+}
+
+func ResourceAdaptiveTeamEndpointRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+	group, endpoint, err := parseTeamMembershipID(d.Id(), "endpoint")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	membership, err := client.GetTeamMembership(ctx, group)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if membership == nil || !slices.Contains(membership.Endpoints, endpoint) {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("group", group); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("endpoint", endpoint); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func ResourceAdaptiveTeamEndpointDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	if err := client.RemoveTeamEndpoint(ctx, d.Get("group").(string), d.Get("endpoint").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return nil
+}
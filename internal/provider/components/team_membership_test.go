@@ -0,0 +1,138 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// A member removed outside Terraform must drop out of state so the next plan
+// re-adds it, and a member still present must keep its ID.
+func TestResourceAdaptiveTeamMemberRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/terraform/team/membership/devs") {
+			http.Error(w, "unexpected path "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "team-1", "name": "devs", "members": ["a@example.com"], "endpoints": []}`))
+	}))
+	defer srv.Close()
+
+	client := adaptive.NewClient("test-token", srv.URL)
+	res := ResourceAdaptiveTeamMember()
+
+	for email, wantID := range map[string]string{
+		"a@example.com": "devs/a@example.com",
+		"b@example.com": "",
+	} {
+		d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{})
+		d.SetId(teamMembershipID("devs", email))
+
+		if diags := ResourceAdaptiveTeamMemberRead(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("read %s returned diagnostics: %+v", email, diags)
+		}
+		if d.Id() != wantID {
+			t.Errorf("read %s: got ID %q want %q", email, d.Id(), wantID)
+		}
+		if wantID != "" && d.Get("email").(string) != email {
+			t.Errorf("read %s: email not set from ID, got %q", email, d.Get("email"))
+		}
+	}
+}
+
+// Updating a group must not send lists that did not change, otherwise members
+// managed by adaptive_group_member would be wiped by a rename.
+func TestResourceAdaptiveTeamUpdate_OmitsUnchangedLists(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := adaptive.NewClient("test-token", srv.URL)
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveTeam().Schema, map[string]interface{}{
+		"name":      "devs",
+		"endpoints": []interface{}{"db"},
+	})
+	d.SetId("team-1")
+
+	if diags := ResourceAdaptiveTeamUpdate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("update returned diagnostics: %+v", diags)
+	}
+	if _, ok := body["Members"]; ok {
+		t.Errorf("unchanged members were sent: %v", body)
+	}
+	if _, ok := body["Endpoints"]; !ok {
+		t.Errorf("changed endpoints were not sent: %v", body)
+	}
+}
+
+// Adopting a group must send both configured lists, even empty ones, so the
+// group ends up with exactly the configured members and endpoints.
+func TestResourceAdaptiveTeamCreate_AdoptSendsFullLists(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/terraform/team/create"):
+			w.WriteHeader(http.StatusConflict)
+		case strings.HasSuffix(r.URL.Path, "/terraform/team/find/devs"):
+			_, _ = w.Write([]byte(`{"id": "team-7", "name": "devs"}`))
+		case strings.HasSuffix(r.URL.Path, "/terraform/team/update/team-7"):
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding request body: %v", err)
+			}
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.Error(w, "unexpected path "+r.URL.Path, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := adaptive.NewClient("test-token", srv.URL)
+	d := schema.TestResourceDataRaw(t, ResourceAdaptiveTeam().Schema, map[string]interface{}{
+		"name":           "devs",
+		"members":        []interface{}{"a@example.com"},
+		"adopt_existing": true,
+	})
+
+	diags := ResourceAdaptiveTeamCreate(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("create returned diagnostics: %+v", diags)
+	}
+	if d.Id() != "team-7" {
+		t.Fatalf("expected team-7 to be adopted, got id %q", d.Id())
+	}
+	if members, ok := body["Members"].([]interface{}); !ok || len(members) != 1 || members[0] != "a@example.com" {
+		t.Errorf("members not replaced: %v", body)
+	}
+	if endpoints, ok := body["Endpoints"].([]interface{}); !ok || len(endpoints) != 0 {
+		t.Errorf("endpoints not cleared: %v", body)
+	}
+}
+
+func TestParseTeamMembershipID(t *testing.T) {
+	group, item, err := parseTeamMembershipID("devs/a@example.com", "email")
+	if err != nil || group != "devs" || item != "a@example.com" {
+		t.Errorf("got (%q, %q, %v)", group, item, err)
+	}
+	group, item, err = parseTeamMembershipID("eng/platform/payments-db", "endpoint")
+	if err != nil || group != "eng/platform" || item != "payments-db" {
+		t.Errorf("group with a slash: got (%q, %q, %v)", group, item, err)
+	}
+	for _, id := range []string{"devs", "/a@example.com", "devs/"} {
+		if _, _, err := parseTeamMembershipID(id, "email"); err == nil {
+			t.Errorf("expected error for ID %q", id)
+		}
+	}
+}
@@ -26,17 +26,30 @@ type AdaptiveRDPTargetConfig struct {
 
 // AdaptiveRDPIntegrationConfiguration mirrors the backend
 // AdaptiveRDPIntegrationConfiguration. Targets is a YAML-encoded list of
-// AdaptiveRDPTargetConfig that the server re-parses via ParseTargets().
+// AdaptiveRDPTargetConfig that the server re-parses via ParseTargets(). An
+// empty Targets is left out so the server keeps the fleet's current targets.
 type AdaptiveRDPIntegrationConfiguration struct {
 	Version string `yaml:"version"`
 	Name    string `yaml:"name"`
-	Targets string `yaml:"targets"`
+	Targets string `yaml:"targets,omitempty"`
 }
 
 func SchemaToAdaptiveRDPIntegrationConfiguration(d *schema.ResourceData) (AdaptiveRDPIntegrationConfiguration, error) {
+	// With manage_targets = false the targets belong to adaptive_rdp_target
+	// resources, so the fleet must neither declare nor overwrite them.
+	if !ManageRDPTargets(d) {
+		if _, ok := d.GetOk("targets"); ok {
+			return AdaptiveRDPIntegrationConfiguration{}, errors.New("`targets` cannot be set when `manage_targets` is false; use adaptive_rdp_target resources instead")
+		}
+		return AdaptiveRDPIntegrationConfiguration{
+			Version: "1.0",
+			Name:    d.Get("name").(string),
+		}, nil
+	}
+
 	raw, ok := d.GetOk("targets")
 	if !ok {
-		return AdaptiveRDPIntegrationConfiguration{}, errors.New("adaptive_rdp requires at least one `targets` block")
+		return AdaptiveRDPIntegrationConfiguration{}, errors.New("adaptive_rdp requires at least one `targets` block, or `manage_targets = false` with adaptive_rdp_target resources")
 	}
 	list := raw.([]interface{})
 
@@ -57,12 +70,9 @@ func SchemaToAdaptiveRDPIntegrationConfiguration(d *schema.ResourceData) (Adapti
 			return AdaptiveRDPIntegrationConfiguration{}, fmt.Errorf("duplicate `targets` id %q: each target must have a unique id", id)
 		}
 		seenIDs[id] = true
-		// The schema marks password Required, but that still admits an
-		// explicit empty string — which the backend accepts and the RDP
-		// login then fails with. Reject it here instead.
 		password := m["password"].(string)
-		if password == "" {
-			return AdaptiveRDPIntegrationConfiguration{}, fmt.Errorf("target %q: `password` must not be empty; RDP connections with blank passwords fail to authenticate", id)
+		if err := checkAdaptiveRDPPassword(id, password); err != nil {
+			return AdaptiveRDPIntegrationConfiguration{}, err
 		}
 		targets = append(targets, AdaptiveRDPTargetConfig{
 			ID:       id,
@@ -88,6 +98,31 @@ func SchemaToAdaptiveRDPIntegrationConfiguration(d *schema.ResourceData) (Adapti
 	}, nil
 }
 
+// checkAdaptiveRDPPassword rejects an empty target password. The schema marks
+// password Required, but that still admits an explicit empty string — which
+// the backend accepts and the RDP login then fails with.
+func checkAdaptiveRDPPassword(targetID, password string) error {
+	if password == "" {
+		return fmt.Errorf("target %q: `password` must not be empty; RDP connections with blank passwords fail to authenticate", targetID)
+	}
+	return nil
+}
+
+// ManageRDPTargets reports whether an adaptive_rdp fleet manages its
+// targets list. manage_targets has no default, so that state written before
+// it existed does not diff, and an unset value means true. d.Get collapses
+// unset to false, so the raw config is read instead.
+func ManageRDPTargets(d *schema.ResourceData) bool {
+	raw := d.GetRawConfig()
+	if !raw.IsNull() && raw.IsKnown() {
+		v := raw.GetAttr("manage_targets")
+		return v.IsNull() || !v.IsKnown() || v.Type() != cty.Bool || v.True()
+	}
+	// Without a raw config, as in unit tests, only an explicit value exists.
+	v, ok := d.GetOkExists("manage_targets")
+	return !ok || v.(bool)
+}
+
 // adaptiveRDPRecordOverrides returns a per-target *bool for the `record` field,
 // distinguishing unset (nil) from an explicit true/false by inspecting the raw
 // config — d.Get would report an unset bool as false.
@@ -0,0 +1,204 @@
+package integrations
+
+/*
+Example resource usage:
+
+resource "adaptive_rdp_target" "win01" {
+	fleet_id  = adaptive_resource.windows_fleet.id
+	target_id = "win01"
+	host      = "10.0.0.5"
+	username  = "Administrator"
+	password  = var.win01_password
+}
+*/
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceAdaptiveRDPTarget manages a single target of an adaptive_rdp fleet.
+// Its ID is "<fleet_id>/<target_id>".
+func ResourceAdaptiveRDPTarget() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAdaptiveRDPTargetCreate,
+		ReadContext:   resourceAdaptiveRDPTargetRead,
+		UpdateContext: resourceAdaptiveRDPTargetUpdate,
+		DeleteContext: resourceAdaptiveRDPTargetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"fleet_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the adaptive_rdp resource the target belongs to. The fleet should set `manage_targets = false`.",
+			},
+			"target_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Unique identifier for the target within the fleet.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Human-friendly name shown in the in-browser target picker.",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Hostname or IP of the Windows server.",
+			},
+			"port": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     3389,
+				Description: "RDP port. Defaults to 3389.",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Username to authenticate with the target.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Password to authenticate with the target. Must not be empty.",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Optional Windows domain for the target.",
+			},
+			"record": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Per-target session-recording override. If unset, inherits the global recording setting (COLLECT_RDP_RECORDINGS).",
+			},
+		},
+	}
+}
+
+func parseAdaptiveRDPTargetID(id string) (fleetID, targetID string, err error) {
+	fleetID, targetID, ok := strings.Cut(id, "/")
+	if !ok || fleetID == "" || targetID == "" {
+		return "", "", fmt.Errorf("invalid ID %q, expected <fleet_id>/<target_id>", id)
+	}
+	return fleetID, targetID, nil
+}
+
+func schemaToAdaptiveRDPTarget(d *schema.ResourceData) (*adaptive.RDPTarget, error) {
+	targetID := d.Get("target_id").(string)
+	password := d.Get("password").(string)
+	if err := checkAdaptiveRDPPassword(targetID, password); err != nil {
+		return nil, err
+	}
+
+	target := &adaptive.RDPTarget{
+		ID:       targetID,
+		Name:     d.Get("name").(string),
+		Host:     d.Get("host").(string),
+		Port:     d.Get("port").(int),
+		Username: d.Get("username").(string),
+		Password: password,
+		Domain:   d.Get("domain").(string),
+	}
+
+	// Same tri-state handling as the fleet's targets blocks: only an
+	// explicit true/false in config overrides the global setting.
+	if raw := d.GetRawConfig(); !raw.IsNull() && raw.IsKnown() {
+		if rec := raw.GetAttr("record"); !rec.IsNull() && rec.IsKnown() && rec.Type() == cty.Bool {
+			v := rec.True()
+			target.Record = &v
+		}
+	}
+	return target, nil
+}
+
+func resourceAdaptiveRDPTargetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+	fleetID := d.Get("fleet_id").(string)
+
+	target, err := schemaToAdaptiveRDPTarget(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := client.CreateRDPTarget(ctx, fleetID, target); err != nil {
+		if adaptive.IsDuplicateName(err) {
+			return diag.Errorf("duplicate target id %q in fleet %s: each target must have a unique id", target.ID, fleetID)
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fleetID + "/" + target.ID)
+	return resourceAdaptiveRDPTargetRead(ctx, d, m)
+}
+
+func resourceAdaptiveRDPTargetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+	fleetID, targetID, err := parseAdaptiveRDPTargetID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	target, err := client.GetRDPTarget(ctx, fleetID, targetID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if target == nil {
+		d.SetId("")
+		return nil
+	}
+
+	// The backend never returns the password, so the configured value stays
+	// in state as is.
+	values := map[string]interface{}{
+		"fleet_id":  fleetID,
+		"target_id": targetID,
+		"name":      target.Name,
+		"host":      target.Host,
+		"port":      target.Port,
+		"username":  target.Username,
+		"domain":    target.Domain,
+		"record":    target.Record != nil && *target.Record,
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func resourceAdaptiveRDPTargetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	target, err := schemaToAdaptiveRDPTarget(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := client.UpdateRDPTarget(ctx, d.Get("fleet_id").(string), target); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceAdaptiveRDPTargetRead(ctx, d, m)
+}
+
+func resourceAdaptiveRDPTargetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*adaptive.Client)
+
+	if err := client.DeleteRDPTarget(ctx, d.Get("fleet_id").(string), d.Get("target_id").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return nil
+}
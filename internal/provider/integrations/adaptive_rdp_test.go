@@ -7,11 +7,13 @@ import (
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/yaml.v2"
 )
 
 func adaptiveRDPTestSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"name": {Type: schema.TypeString, Optional: true},
+		"name":           {Type: schema.TypeString, Optional: true},
+		"manage_targets": {Type: schema.TypeBool, Optional: true},
 		"targets": {
 			Type:     schema.TypeList,
 			Optional: true,
@@ -155,3 +157,66 @@ func TestAdaptiveRDPRecordOverrides_NoRawConfig(t *testing.T) {
 		t.Errorf("expected nil override when raw config is unavailable, got %v", *overrides[0])
 	}
 }
+
+func TestSchemaToAdaptiveRDPIntegrationConfiguration_UnmanagedTargets(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, adaptiveRDPTestSchema(), map[string]interface{}{
+		"name":           "rdp-fleet",
+		"manage_targets": false,
+	})
+
+	cfg, err := SchemaToAdaptiveRDPIntegrationConfiguration(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(out), "targets") {
+		t.Fatalf("unmanaged fleet must not send targets, got:\n%s", out)
+	}
+}
+
+func TestSchemaToAdaptiveRDPIntegrationConfiguration_UnmanagedTargetsConflict(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, adaptiveRDPTestSchema(), map[string]interface{}{
+		"name":           "rdp-fleet",
+		"manage_targets": false,
+		"targets": []interface{}{
+			map[string]interface{}{"id": "server-1", "host": "10.0.0.5", "username": "admin", "password": "pw"},
+		},
+	})
+
+	if _, err := SchemaToAdaptiveRDPIntegrationConfiguration(d); err == nil {
+		t.Fatal("expected an error when targets are set on an unmanaged fleet")
+	}
+}
+
+func TestSchemaToAdaptiveRDPTarget(t *testing.T) {
+	res := ResourceAdaptiveRDPTarget()
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"fleet_id":  "fleet-1",
+		"target_id": "server-1",
+		"host":      "10.0.0.5",
+		"username":  "admin",
+		"password":  "",
+	})
+	if _, err := schemaToAdaptiveRDPTarget(d); err == nil || !strings.Contains(err.Error(), "server-1") {
+		t.Fatalf("expected a password error naming the target, got: %v", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"fleet_id":  "fleet-1",
+		"target_id": "server-1",
+		"host":      "10.0.0.5",
+		"username":  "admin",
+		"password":  "pw",
+	})
+	target, err := schemaToAdaptiveRDPTarget(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Port != 3389 || target.Record != nil {
+		t.Fatalf("unexpected defaults: port=%d record=%v", target.Port, target.Record)
+	}
+}
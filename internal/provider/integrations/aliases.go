@@ -0,0 +1,87 @@
+package integrations
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AttributeAliases maps each canonical adaptive_resource attribute to the
+// deprecated attributes that used to hold the same setting for some
+// integration types. Converters read the canonical name through
+// CanonicalString, so either spelling works for every type.
+var AttributeAliases = map[string][]string{
+	"host":     {"hostname"},
+	"region":   {"region_name", "aws_region_name"},
+	"arn":      {"aws_arn"},
+	"password": {"database_password"},
+}
+
+// CanonicalAttributes returns the keys of AttributeAliases in a stable order.
+func CanonicalAttributes() []string {
+	keys := make([]string, 0, len(AttributeAliases))
+	for k := range AttributeAliases {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DeprecatedAliasMessage is the Deprecated text of an alias attribute.
+func DeprecatedAliasMessage(canonical string) string {
+	return fmt.Sprintf("Use `%s` instead.", canonical)
+}
+
+// ResolveAttributeAlias returns the value of canonical given the values of it
+// and its aliases, in AttributeAliases order; empty values are unset. It is an
+// error for two of them to be set to different values.
+func ResolveAttributeAlias(canonical string, values map[string]string) (string, error) {
+	resolved, from := values[canonical], canonical
+	for _, alias := range AttributeAliases[canonical] {
+		v := values[alias]
+		if v == "" {
+			continue
+		}
+		if resolved == "" {
+			resolved, from = v, alias
+			continue
+		}
+		if v != resolved {
+			return "", fmt.Errorf("`%s` and `%s` are set to different values; `%s` is deprecated, set only `%s`", from, alias, alias, canonical)
+		}
+	}
+	return resolved, nil
+}
+
+// CheckAttributeAliases reports canonical attributes and aliases set to
+// conflicting values.
+func CheckAttributeAliases(d SchemaGetter) error {
+	for _, canonical := range CanonicalAttributes() {
+		if _, err := ResolveAttributeAlias(canonical, aliasValues(d, canonical)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CanonicalString returns the value of canonical, falling back to the first
+// of its deprecated aliases that is set.
+func CanonicalString(d SchemaGetter, canonical string) string {
+	values := aliasValues(d, canonical)
+	if v := values[canonical]; v != "" {
+		return v
+	}
+	for _, alias := range AttributeAliases[canonical] {
+		if v := values[alias]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func aliasValues(d SchemaGetter, canonical string) map[string]string {
+	values := make(map[string]string)
+	for _, k := range append([]string{canonical}, AttributeAliases[canonical]...) {
+		values[k], _ = d.Get(k).(string)
+	}
+	return values
+}
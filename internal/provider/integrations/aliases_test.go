@@ -0,0 +1,71 @@
+package integrations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResolveAttributeAlias(t *testing.T) {
+	tests := []struct {
+		canonical string
+		values    map[string]string
+		want      string
+		wantErr   string
+	}{
+		{canonical: "host", values: map[string]string{"host": "db"}, want: "db"},
+		{canonical: "host", values: map[string]string{"hostname": "db"}, want: "db"},
+		{canonical: "host", values: map[string]string{"host": "db", "hostname": "db"}, want: "db"},
+		{canonical: "host", values: map[string]string{"host": "db", "hostname": "other"}, wantErr: "`host` and `hostname`"},
+		{canonical: "region", values: map[string]string{"region_name": "us-east-1", "aws_region_name": "eu-west-1"}, wantErr: "`region_name` and `aws_region_name`"},
+		{canonical: "region", values: map[string]string{"aws_region_name": "eu-west-1"}, want: "eu-west-1"},
+		{canonical: "arn", values: map[string]string{}, want: ""},
+	}
+	for _, tt := range tests {
+		got, err := ResolveAttributeAlias(tt.canonical, tt.values)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s %v: error = %v, want one containing %q", tt.canonical, tt.values, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s %v: got %q, %v; want %q", tt.canonical, tt.values, got, err, tt.want)
+		}
+	}
+}
+
+func TestCanonicalStringReadsAliases(t *testing.T) {
+	s := map[string]*schema.Schema{}
+	for _, k := range []string{"name", "username", "webui_port", "login_url"} {
+		s[k] = &schema.Schema{Type: schema.TypeString, Optional: true}
+	}
+	for _, k := range CanonicalAttributes() {
+		s[k] = &schema.Schema{Type: schema.TypeString, Optional: true}
+		for _, alias := range AttributeAliases[k] {
+			s[alias] = &schema.Schema{Type: schema.TypeString, Optional: true}
+		}
+	}
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{
+		"name":              "fw",
+		"host":              "fw.example.com",
+		"database_password": "pw",
+		"aws_arn":           "arn:aws:iam::123456789012:role/reader",
+		"region_name":       "us-east-1",
+	})
+
+	// Network devices used to read `hostname` and AWS Secrets Manager
+	// `aws_arn`; both spellings now reach every converter.
+	cfg := SchemaToPaloAltoNGFWIntegrationConfiguration(d)
+	if cfg.Hostname != "fw.example.com" || cfg.Password != "pw" {
+		t.Errorf("palo alto config = %+v", cfg)
+	}
+	sm := SchemaToAWSSecretsManagerConfiguration(d)
+	if sm.AWSARN != "arn:aws:iam::123456789012:role/reader" || sm.AWSRegionName != "us-east-1" {
+		t.Errorf("secrets manager config = %+v", sm)
+	}
+	if err := CheckAttributeAliases(d); err != nil {
+		t.Errorf("unexpected conflict: %v", err)
+	}
+}
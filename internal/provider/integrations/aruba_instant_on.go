@@ -14,10 +14,10 @@ type ArubaInstantOnIntegrationConfiguration struct {
 func SchemaToArubaInstantOnIntegrationConfiguration(d *schema.ResourceData) ArubaInstantOnIntegrationConfiguration {
 	return ArubaInstantOnIntegrationConfiguration{
 		Name:     d.Get("name").(string),
-		Host:     d.Get("host").(string),
+		Host:     CanonicalString(d, "host"),
 		Port:     d.Get("port").(string),
 		Username: d.Get("username").(string),
-		Password: d.Get("password").(string),
+		Password: CanonicalString(d, "password"),
 		APIToken: d.Get("api_token").(string),
 	}
 }
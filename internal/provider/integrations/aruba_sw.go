@@ -12,8 +12,8 @@ type ArubaSWIntegrationConfiguration struct {
 func SchemaToArubaSWIntegrationConfiguration(d *schema.ResourceData) ArubaSWIntegrationConfiguration {
 	return ArubaSWIntegrationConfiguration{
 		Name:     d.Get("name").(string),
-		Hostname: d.Get("hostname").(string),
+		Hostname: CanonicalString(d, "host"),
 		Username: d.Get("username").(string),
-		Password: d.Get("password").(string),
+		Password: CanonicalString(d, "password"),
 	}
 }
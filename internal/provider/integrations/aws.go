@@ -70,7 +70,7 @@ func SchemaToAWSIntegrationConfiguration(d *schema.ResourceData) AWSCLIIntegrati
 	return AWSCLIIntegrationConfiguration{
 		Version:            "1.0",
 		Name:               d.Get("name").(string),
-		AWSRegionName:      d.Get("region_name").(string),
+		AWSRegionName:      CanonicalString(d, "region"),
 		AWSAccessKeyID:     d.Get("access_key_id").(string),
 		AWSSecretAccessKey: d.Get("secret_access_key").(string),
 	}
@@ -113,7 +113,7 @@ func resourceAdaptiveAWSUpdate(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "aws", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "aws", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
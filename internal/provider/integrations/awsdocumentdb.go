@@ -88,7 +88,7 @@ func resourceAdaptiveAWSDocumentDBUpdate(ctx context.Context, d *schema.Resource
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "awsdocumentdb", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "awsdocumentdb", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
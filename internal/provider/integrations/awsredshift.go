@@ -13,14 +13,18 @@ type AWSRedshiftIntegrationConfiguration struct {
 	SSLMode      string `yaml:"sslMode"`
 }
 
-func SchemaToAWSRedshiftIntegrationConfiguration(d *schema.ResourceData) AWSRedshiftIntegrationConfiguration {
+func SchemaToAWSRedshiftIntegrationConfiguration(d *schema.ResourceData) (AWSRedshiftIntegrationConfiguration, error) {
+	conn, err := SQLConnectionFromSchema(d, "awsredshift")
+	if err != nil {
+		return AWSRedshiftIntegrationConfiguration{}, err
+	}
 	return AWSRedshiftIntegrationConfiguration{
 		Name:         d.Get("name").(string),
-		Username:     d.Get("username").(string),
-		Password:     d.Get("password").(string),
-		DatabaseName: d.Get("database_name").(string),
-		HostName:     d.Get("host").(string),
-		Port:         d.Get("port").(string),
+		Username:     conn.Username,
+		Password:     conn.Password,
+		DatabaseName: conn.DatabaseName,
+		HostName:     conn.Host,
+		Port:         conn.Port,
 		// SSLMode:      d.Get("ssl_mode").(string),
-	}
+	}, nil
 }
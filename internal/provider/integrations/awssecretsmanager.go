@@ -12,7 +12,7 @@ type AWSSecretsManagerConfiguration struct {
 func SchemaToAWSSecretsManagerConfiguration(d *schema.ResourceData) AWSSecretsManagerConfiguration {
 	return AWSSecretsManagerConfiguration{
 		Name:          d.Get("name").(string),
-		AWSRegionName: d.Get("aws_region_name").(string),
-		AWSARN:        d.Get("aws_arn").(string),
+		AWSRegionName: CanonicalString(d, "region"),
+		AWSARN:        CanonicalString(d, "arn"),
 	}
 }
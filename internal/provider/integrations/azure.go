@@ -114,7 +114,7 @@ func resourceAdaptiveAzureUpdate(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "azure", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "azure", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
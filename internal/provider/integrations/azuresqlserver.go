@@ -21,9 +21,9 @@ func SchemaToAzureSQLServerIntegrationConfiguration(d *schema.ResourceData) (Azu
 		return AzureSQLServerIntegrationConfiguration{}, errors.New("name attribute is required and must be a non-empty string")
 	}
 
-	hostname, ok := d.Get("hostname").(string)
-	if !ok || hostname == "" {
-		return AzureSQLServerIntegrationConfiguration{}, errors.New("hostname attribute is required and must be a non-empty string")
+	hostname := CanonicalString(d, "host")
+	if hostname == "" {
+		return AzureSQLServerIntegrationConfiguration{}, errors.New("host attribute is required and must be a non-empty string")
 	}
 
 	port, ok := d.Get("port").(string)
@@ -36,8 +36,8 @@ func SchemaToAzureSQLServerIntegrationConfiguration(d *schema.ResourceData) (Azu
 		return AzureSQLServerIntegrationConfiguration{}, errors.New("username attribute is required and must be a non-empty string")
 	}
 
-	password, ok := d.Get("password").(string)
-	if !ok || password == "" {
+	password := CanonicalString(d, "password")
+	if password == "" {
 		return AzureSQLServerIntegrationConfiguration{}, errors.New("password attribute is required and must be a non-empty string")
 	}
 
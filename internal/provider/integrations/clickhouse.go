@@ -12,19 +12,19 @@ type ClickHouseIntegrationConfiguration struct {
 	SSLMode      string `yaml:"sslMode"`
 }
 
-func SchemaToClickHouseIntegrationConfiguration(d *schema.ResourceData) ClickHouseIntegrationConfiguration {
-	sslMode := ""
-	if v, ok := d.GetOk("ssl_mode"); ok {
-		sslMode = v.(string)
+func SchemaToClickHouseIntegrationConfiguration(d *schema.ResourceData) (ClickHouseIntegrationConfiguration, error) {
+	conn, err := SQLConnectionFromSchema(d, "clickhouse")
+	if err != nil {
+		return ClickHouseIntegrationConfiguration{}, err
 	}
 
 	return ClickHouseIntegrationConfiguration{
 		Name:         d.Get("name").(string),
-		Username:     d.Get("username").(string),
-		Password:     d.Get("password").(string),
-		DatabaseName: d.Get("database_name").(string),
-		HostName:     d.Get("host").(string),
-		Port:         d.Get("port").(string),
-		SSLMode:      sslMode,
-	}
+		Username:     conn.Username,
+		Password:     conn.Password,
+		DatabaseName: conn.DatabaseName,
+		HostName:     conn.Host,
+		Port:         conn.Port,
+		SSLMode:      conn.SSLMode,
+	}, nil
 }
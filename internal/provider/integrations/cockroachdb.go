@@ -99,14 +99,10 @@ func resourceAdaptiveCockroachDB() *schema.Resource {
 	}
 }
 
-func SchemaToCockroachDBIntegrationConfiguration(d *schema.ResourceData) CockroachDBIntegrationConfiguration {
-	sslMode := ""
-	if d.Get("ssl_mode") != nil {
-		if _sslMode, ok := d.Get("ssl_mode").(string); ok {
-			sslMode = _sslMode
-		} else if !ok {
-			sslMode = ""
-		}
+func SchemaToCockroachDBIntegrationConfiguration(d *schema.ResourceData) (CockroachDBIntegrationConfiguration, error) {
+	conn, err := SQLConnectionFromSchema(d, "cockroachdb")
+	if err != nil {
+		return CockroachDBIntegrationConfiguration{}, err
 	}
 
 	tlsRootCert := ""
@@ -118,20 +114,23 @@ func SchemaToCockroachDBIntegrationConfiguration(d *schema.ResourceData) Cockroa
 
 	return CockroachDBIntegrationConfiguration{
 		Name:         d.Get("name").(string),
-		Username:     d.Get("username").(string),
-		Password:     d.Get("password").(string),
-		DatabaseName: d.Get("database_name").(string),
-		HostName:     d.Get("host").(string),
-		Port:         d.Get("port").(string),
-		SSLMode:      sslMode,
+		Username:     conn.Username,
+		Password:     conn.Password,
+		DatabaseName: conn.DatabaseName,
+		HostName:     conn.Host,
+		Port:         conn.Port,
+		SSLMode:      conn.SSLMode,
 		RootCert:     strings.TrimSpace(tlsRootCert),
-	}
+	}, nil
 }
 
 func resourceAdaptiveCockroachDBCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*adaptive.Client)
 
-	obj := SchemaToCockroachDBIntegrationConfiguration(d)
+	obj, err := SchemaToCockroachDBIntegrationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	config, err := yaml.Marshal(obj)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
@@ -159,13 +158,16 @@ func resourceAdaptiveCockroachDBUpdate(ctx context.Context, d *schema.ResourceDa
 	client := m.(*adaptive.Client)
 	resourceID := d.Id()
 
-	obj := SchemaToCockroachDBIntegrationConfiguration(d)
+	obj, err := SchemaToCockroachDBIntegrationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	config, err := yaml.Marshal(obj)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "cockroachdb", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "cockroachdb", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -15,7 +15,7 @@ func SchemaToElasticsearchIntegrationConfiguration(d *schema.ResourceData) Elast
 		Name:     d.Get("name").(string),
 		Url:      d.Get("uri").(string),
 		Username: d.Get("username").(string),
-		Password: d.Get("password").(string),
+		Password: CanonicalString(d, "password"),
 		Index:    d.Get("index").(string),
 	}
 }
@@ -17,14 +17,14 @@ type FortinetNGFWIntegrationConfiguration struct {
 
 func SchemaToFortinetNGFWIntegrationConfiguration(d *schema.ResourceData) FortinetNGFWIntegrationConfiguration {
 	return FortinetNGFWIntegrationConfiguration{
-		Hostname:  d.Get("hostname").(string),
+		Hostname:  CanonicalString(d, "host"),
 		LoginUrl:  d.Get("uri").(string),
 		Name:      d.Get("name").(string),
 		Port:      d.Get("port").(string),
 		Type:      "fortinet_ngfw",
 		UseProxy:  d.Get("use_proxy").(bool),
 		Username:  d.Get("username").(string),
-		Password:  d.Get("password").(string),
+		Password:  CanonicalString(d, "password"),
 		Version:   "1.0",
 		WebuiPort: d.Get("webui_port").(string),
 	}
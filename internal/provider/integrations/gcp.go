@@ -103,7 +103,7 @@ func resourceAdaptiveGCPUpdate(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "gcp", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "gcp", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -17,13 +17,13 @@ type HPESwitchIntegrationConfiguration struct {
 
 func SchemaToHPESwitchIntegrationConfiguration(d *schema.ResourceData) HPESwitchIntegrationConfiguration {
 	return HPESwitchIntegrationConfiguration{
-		Hostname:  d.Get("hostname").(string),
+		Hostname:  CanonicalString(d, "host"),
 		LoginUrl:  d.Get("uri").(string),
 		Name:      d.Get("name").(string),
 		Port:      d.Get("port").(string),
 		UseProxy:  d.Get("use_proxy").(bool),
 		Username:  d.Get("username").(string),
-		Password:  d.Get("password").(string),
+		Password:  CanonicalString(d, "password"),
 		WebuiPort: d.Get("webui_port").(string),
 	}
 }
@@ -0,0 +1,196 @@
+package integrations
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// kubeconfig is the subset of a kubeconfig file the integration needs. Paths
+// to local files (certificate-authority, client-certificate, client-key,
+// tokenFile) are rejected: they would name files on the machine running
+// Terraform, not on the Adaptive agent.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string                `yaml:"token"`
+			TokenFile             string                `yaml:"tokenFile"`
+			ClientCertificate     string                `yaml:"client-certificate"`
+			ClientCertificateData string                `yaml:"client-certificate-data"`
+			ClientKey             string                `yaml:"client-key"`
+			ClientKeyData         string                `yaml:"client-key-data"`
+			Impersonate           string                `yaml:"as"`
+			ImpersonateGroups     []string              `yaml:"as-groups"`
+			Exec                  *KubernetesExecConfig `yaml:"exec"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// KubernetesExecConfig is a client-go exec credential plugin, such as
+// `aws eks get-token` or `gke-gcloud-auth-plugin`. The Adaptive agent runs it
+// to obtain short-lived credentials.
+type KubernetesExecConfig struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Command    string             `yaml:"command"`
+	Args       []string           `yaml:"args,omitempty"`
+	Env        []KubernetesEnvVar `yaml:"env,omitempty"`
+}
+
+type KubernetesEnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// kubernetesCredentials is what a kubeconfig context resolves to.
+type kubernetesCredentials struct {
+	APIServer         string
+	CACert            string
+	Token             string
+	ClientCert        string
+	ClientKey         string
+	Exec              *KubernetesExecConfig
+	ImpersonateUser   string
+	ImpersonateGroups []string
+	Namespace         string
+}
+
+// parseKubeconfig resolves the named context, or the current context when
+// contextName is empty, into credentials.
+func parseKubeconfig(content, contextName string) (kubernetesCredentials, error) {
+	var creds kubernetesCredentials
+	var kc kubeconfig
+	if err := yaml.Unmarshal([]byte(content), &kc); err != nil {
+		return creds, fmt.Errorf("`kubeconfig` is not valid YAML: %w", err)
+	}
+
+	if contextName == "" {
+		contextName = kc.CurrentContext
+	}
+	if contextName == "" {
+		return creds, errors.New("`kubeconfig` has no current-context; set `kubeconfig_context`")
+	}
+
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == contextName {
+			clusterName, userName, creds.Namespace = c.Context.Cluster, c.Context.User, c.Context.Namespace
+			found = true
+			break
+		}
+	}
+	if !found {
+		return creds, fmt.Errorf("`kubeconfig` has no context %q", contextName)
+	}
+
+	found = false
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		found = true
+		if c.Cluster.CertificateAuthority != "" {
+			return creds, fmt.Errorf("kubeconfig cluster %q references a local certificate-authority file; inline it as certificate-authority-data", clusterName)
+		}
+		creds.APIServer = c.Cluster.Server
+		if c.Cluster.CertificateAuthorityData != "" {
+			ca, err := base64.StdEncoding.DecodeString(c.Cluster.CertificateAuthorityData)
+			if err != nil {
+				return creds, fmt.Errorf("kubeconfig cluster %q: certificate-authority-data is not valid base64: %w", clusterName, err)
+			}
+			creds.CACert = string(ca)
+		}
+		break
+	}
+	if !found {
+		return creds, fmt.Errorf("`kubeconfig` context %q refers to unknown cluster %q", contextName, clusterName)
+	}
+	if creds.APIServer == "" {
+		return creds, fmt.Errorf("kubeconfig cluster %q has no server", clusterName)
+	}
+
+	found = false
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+		found = true
+		user := u.User
+		if user.TokenFile != "" || user.ClientCertificate != "" || user.ClientKey != "" {
+			return creds, fmt.Errorf("kubeconfig user %q references local credential files; inline them as token, client-certificate-data and client-key-data", userName)
+		}
+		creds.Token = user.Token
+		creds.Exec = user.Exec
+		creds.ImpersonateUser = user.Impersonate
+		creds.ImpersonateGroups = user.ImpersonateGroups
+		for _, f := range []struct {
+			data string
+			dst  *string
+			name string
+		}{
+			{user.ClientCertificateData, &creds.ClientCert, "client-certificate-data"},
+			{user.ClientKeyData, &creds.ClientKey, "client-key-data"},
+		} {
+			if f.data == "" {
+				continue
+			}
+			b, err := base64.StdEncoding.DecodeString(f.data)
+			if err != nil {
+				return creds, fmt.Errorf("kubeconfig user %q: %s is not valid base64: %w", userName, f.name, err)
+			}
+			*f.dst = string(b)
+		}
+		break
+	}
+	if !found {
+		return creds, fmt.Errorf("`kubeconfig` context %q refers to unknown user %q", contextName, userName)
+	}
+	if creds.Exec != nil && creds.Exec.Command == "" {
+		return creds, fmt.Errorf("kubeconfig user %q has an exec plugin without a command", userName)
+	}
+
+	return creds, nil
+}
+
+// validateKubernetesCredentials checks the CA bundle and client key pair
+// locally, so a bad certificate fails the plan instead of the connection.
+func validateKubernetesCredentials(creds kubernetesCredentials) error {
+	if creds.CACert != "" {
+		if _, err := parseCertificatesPEM(creds.CACert); err != nil {
+			return fmt.Errorf("cluster CA certificate is invalid: %w", err)
+		}
+	}
+	if (creds.ClientCert == "") != (creds.ClientKey == "") {
+		return errors.New("`client_certificate` and `client_key` must be set together")
+	}
+	if creds.ClientCert != "" {
+		if _, err := tls.X509KeyPair([]byte(creds.ClientCert), []byte(creds.ClientKey)); err != nil {
+			return fmt.Errorf("client certificate and key do not form a valid pair: %w", err)
+		}
+	}
+	if creds.ImpersonateUser == "" && len(creds.ImpersonateGroups) > 0 {
+		return errors.New("`impersonate_groups` requires a user to impersonate, set by `impersonate_user` or by `as` in `kubeconfig`")
+	}
+	return nil
+}
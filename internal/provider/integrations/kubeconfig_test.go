@@ -0,0 +1,198 @@
+package integrations
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testCertificate returns a self-signed PEM certificate and its PEM key.
+func testCertificate(t *testing.T, cn string) (certPEM, keyPEM string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func b64(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+func TestParseKubeconfig(t *testing.T) {
+	ca, _ := testCertificate(t, "cluster-ca")
+	clientCert, clientKey := testCertificate(t, "admin")
+
+	content := `
+apiVersion: v1
+kind: Config
+current-context: eks
+clusters:
+- name: eks-cluster
+  cluster:
+    server: https://eks.example.com
+    certificate-authority-data: ` + b64(ca) + `
+- name: kind-cluster
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: eks
+  context:
+    cluster: eks-cluster
+    user: eks-user
+    namespace: tools
+- name: kind
+  context:
+    cluster: kind-cluster
+    user: kind-admin
+users:
+- name: eks-user
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: aws
+      args: ["eks", "get-token", "--cluster-name", "prod"]
+      env:
+      - name: AWS_PROFILE
+        value: prod
+- name: kind-admin
+  user:
+    client-certificate-data: ` + b64(clientCert) + `
+    client-key-data: ` + b64(clientKey) + `
+`
+
+	creds, err := parseKubeconfig(content, "")
+	if err != nil {
+		t.Fatalf("current context: %v", err)
+	}
+	if creds.APIServer != "https://eks.example.com" || creds.CACert != ca || creds.Namespace != "tools" {
+		t.Errorf("unexpected cluster settings: %+v", creds)
+	}
+	if creds.Exec == nil || creds.Exec.Command != "aws" || len(creds.Exec.Args) != 4 || creds.Exec.Env[0].Value != "prod" {
+		t.Errorf("exec plugin not parsed: %+v", creds.Exec)
+	}
+
+	creds, err = parseKubeconfig(content, "kind")
+	if err != nil {
+		t.Fatalf("kind context: %v", err)
+	}
+	if creds.ClientCert != clientCert || creds.ClientKey != clientKey {
+		t.Errorf("client certificate not decoded")
+	}
+	if err := validateKubernetesCredentials(creds); err != nil {
+		t.Errorf("valid credentials rejected: %v", err)
+	}
+
+	if _, err := parseKubeconfig(content, "missing"); err == nil {
+		t.Error("expected an error for an unknown context")
+	}
+}
+
+func TestParseKubeconfig_RejectsLocalFiles(t *testing.T) {
+	content := `
+current-context: c
+clusters:
+- name: c
+  cluster:
+    server: https://k8s.example.com
+    certificate-authority: /home/me/.kube/ca.crt
+contexts:
+- name: c
+  context: {cluster: c, user: u}
+users:
+- name: u
+  user: {token: abc}
+`
+	if _, err := parseKubeconfig(content, ""); err == nil || !strings.Contains(err.Error(), "certificate-authority-data") {
+		t.Fatalf("expected a local file error, got %v", err)
+	}
+}
+
+func TestValidateKubernetesCredentials(t *testing.T) {
+	ca, _ := testCertificate(t, "cluster-ca")
+	cert, _ := testCertificate(t, "admin")
+	_, otherKey := testCertificate(t, "other")
+
+	tests := map[string]kubernetesCredentials{
+		"garbage CA":          {CACert: "not a certificate"},
+		"key instead of CA":   {CACert: otherKey},
+		"cert without key":    {CACert: ca, ClientCert: cert},
+		"mismatched key":      {ClientCert: cert, ClientKey: otherKey},
+		"groups without user": {ImpersonateGroups: []string{"system:masters"}},
+	}
+	for name, creds := range tests {
+		if err := validateKubernetesCredentials(creds); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// impersonate_groups may rely on the user named by `as` in the kubeconfig.
+func TestKubernetesCredentialsFromSchema_ImpersonateGroups(t *testing.T) {
+	kubeconfig := func(as string) string {
+		return `
+apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev-user
+users:
+- name: dev-user
+  user:
+    token: abc
+    as: "` + as + `"
+`
+	}
+	s := map[string]*schema.Schema{
+		"kubeconfig":         {Type: schema.TypeString, Optional: true},
+		"impersonate_user":   {Type: schema.TypeString, Optional: true},
+		"impersonate_groups": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	groups := []interface{}{"system:masters"}
+
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{"kubeconfig": kubeconfig("alice"), "impersonate_groups": groups})
+	creds, err := kubernetesCredentialsFromSchema(d)
+	if err != nil {
+		t.Fatalf("user from kubeconfig rejected: %v", err)
+	}
+	if creds.ImpersonateUser != "alice" || len(creds.ImpersonateGroups) != 1 {
+		t.Errorf("creds = %+v", creds)
+	}
+
+	d = schema.TestResourceDataRaw(t, s, map[string]interface{}{"kubeconfig": kubeconfig(""), "impersonate_groups": groups})
+	if _, err := kubernetesCredentialsFromSchema(d); err == nil || !strings.Contains(err.Error(), "requires a user to impersonate") {
+		t.Errorf("expected groups without any user to be rejected, got %v", err)
+	}
+}
@@ -16,9 +16,75 @@ type KubernetesIntegrationConfiguration struct {
 	AnnotationsBytes  string `yaml:"annotationsBytes,omitempty"`
 	NodeSelectorBytes string `yaml:"nodeSelectorBytes,omitempty"`
 	NodeAffinityBytes string `yaml:"affinityBytes,omitempty"`
+
+	ClientCert        string                `yaml:"clientcrt,omitempty"`
+	ClientKey         string                `yaml:"clientkey,omitempty"`
+	Exec              *KubernetesExecConfig `yaml:"exec,omitempty"`
+	ImpersonateUser   string                `yaml:"impersonateUser,omitempty"`
+	ImpersonateGroups []string              `yaml:"impersonateGroups,omitempty"`
+	// Namespaces scopes the integration to several namespaces. Namespace
+	// stays set to the first one for agents that only read it.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+}
+
+// kubernetesCredentialsFromSchema resolves the connection either from
+// `kubeconfig` or from the individual attributes, and validates it.
+func kubernetesCredentialsFromSchema(d SchemaGetter) (kubernetesCredentials, error) {
+	var creds kubernetesCredentials
+	if content, _ := d.Get("kubeconfig").(string); content != "" {
+		contextName, _ := d.Get("kubeconfig_context").(string)
+		var err error
+		if creds, err = parseKubeconfig(content, contextName); err != nil {
+			return creds, err
+		}
+	} else {
+		creds.APIServer, _ = d.Get("api_server").(string)
+		creds.CACert, _ = d.Get("cluster_cert").(string)
+		creds.Token, _ = d.Get("cluster_token").(string)
+		creds.ClientCert, _ = d.Get("client_certificate").(string)
+		creds.ClientKey, _ = d.Get("client_key").(string)
+	}
+
+	if user, _ := d.Get("impersonate_user").(string); user != "" {
+		creds.ImpersonateUser = user
+	}
+	if groups, _ := d.Get("impersonate_groups").([]interface{}); len(groups) > 0 {
+		creds.ImpersonateGroups = nil
+		for _, g := range groups {
+			s, _ := g.(string)
+			creds.ImpersonateGroups = append(creds.ImpersonateGroups, s)
+		}
+	}
+
+	creds.CACert = strings.TrimSpace(creds.CACert)
+	creds.Token = strings.TrimSpace(creds.Token)
+	return creds, validateKubernetesCredentials(creds)
+}
+
+// ValidateKubernetesConnection checks the Kubernetes connection settings at
+// plan time.
+func ValidateKubernetesConnection(d SchemaGetter) error {
+	_, err := kubernetesCredentialsFromSchema(d)
+	return err
 }
 
-func SchemaToKubernetesIntegrationConfiguration(d *schema.ResourceData) KubernetesIntegrationConfiguration {
+func kubernetesNamespacesFromSchema(d SchemaGetter, fallback string) (namespace string, namespaces []string) {
+	list, _ := d.Get("namespaces").([]interface{})
+	for _, n := range list {
+		s, _ := n.(string)
+		namespaces = append(namespaces, s)
+	}
+	namespace, _ = d.Get("namespace").(string)
+	if namespace == "" && len(namespaces) > 0 {
+		namespace = namespaces[0]
+	}
+	if namespace == "" {
+		namespace = fallback
+	}
+	return namespace, namespaces
+}
+
+func SchemaToKubernetesIntegrationConfiguration(d *schema.ResourceData) (KubernetesIntegrationConfiguration, error) {
 	tolerationsBytes := ""
 	if v, ok := d.GetOk("tolerations"); ok {
 		tolerationsBytes = v.(string)
@@ -39,17 +105,48 @@ func SchemaToKubernetesIntegrationConfiguration(d *schema.ResourceData) Kubernet
 		nodeAffinityBytes = v.(string)
 	}
 
+	// The native blocks conflict with their string forms in the schema, so
+	// at most one of each pair is set.
+	scheduling, err := KubernetesSchedulingFromSchema(d)
+	if err != nil {
+		return KubernetesIntegrationConfiguration{}, err
+	}
+	if scheduling.Tolerations != "" {
+		tolerationsBytes = scheduling.Tolerations
+	}
+	if scheduling.Annotations != "" {
+		annotationsBytes = scheduling.Annotations
+	}
+	if scheduling.NodeSelector != "" {
+		nodeSelectorBytes = scheduling.NodeSelector
+	}
+	if scheduling.NodeAffinity != "" {
+		nodeAffinityBytes = scheduling.NodeAffinity
+	}
+
+	creds, err := kubernetesCredentialsFromSchema(d)
+	if err != nil {
+		return KubernetesIntegrationConfiguration{}, err
+	}
+	namespace, namespaces := kubernetesNamespacesFromSchema(d, creds.Namespace)
+
 	k := KubernetesIntegrationConfiguration{
 		Name:              d.Get("name").(string),
-		ApiServer:         d.Get("api_server").(string),
-		ClusterCerts:      strings.TrimSpace(d.Get("cluster_cert").(string)),
-		ClusterToken:      strings.TrimSpace(d.Get("cluster_token").(string)),
-		Namespace:         d.Get("namespace").(string),
+		ApiServer:         creds.APIServer,
+		ClusterCerts:      creds.CACert,
+		ClusterToken:      creds.Token,
+		Namespace:         namespace,
+		Namespaces:        namespaces,
+		ClientCert:        creds.ClientCert,
+		ClientKey:         creds.ClientKey,
+		Exec:              creds.Exec,
+		ImpersonateUser:   creds.ImpersonateUser,
+		ImpersonateGroups: creds.ImpersonateGroups,
 		TolerationsBytes:  tolerationsBytes,
 		AnnotationsBytes:  annotationsBytes,
 		NodeSelectorBytes: nodeSelectorBytes,
 		NodeAffinityBytes: nodeAffinityBytes,
 	}
 
-	return k
+	return k, nil
 }
@@ -0,0 +1,313 @@
+package integrations
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// The native scheduling blocks render to the same YAML the string attributes
+// (tolerations, annotations, node_selector, node_affinity) carry, so the
+// backend sees no difference between the two forms.
+
+var (
+	ValidTolerationOperators = []string{"Equal", "Exists"}
+	ValidTolerationEffects   = []string{"", "NoSchedule", "PreferNoSchedule", "NoExecute"}
+	ValidAffinityOperators   = []string{"In", "NotIn", "Exists", "DoesNotExist", "Gt", "Lt"}
+
+	k8sNameRE  = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	k8sDNSRE   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	k8sValueRE = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+)
+
+// SchemaGetter is satisfied by both schema.ResourceData and
+// schema.ResourceDiff, so blocks can be checked at plan time and rendered at
+// apply time by the same code.
+type SchemaGetter interface {
+	Get(key string) interface{}
+}
+
+type kubernetesToleration struct {
+	Key               string `yaml:"key,omitempty"`
+	Operator          string `yaml:"operator,omitempty"`
+	Value             string `yaml:"value,omitempty"`
+	Effect            string `yaml:"effect,omitempty"`
+	TolerationSeconds *int   `yaml:"tolerationSeconds,omitempty"`
+}
+
+type kubernetesMatchExpression struct {
+	Key      string   `yaml:"key"`
+	Operator string   `yaml:"operator"`
+	Values   []string `yaml:"values,omitempty"`
+}
+
+type kubernetesNodeSelectorTerm struct {
+	MatchExpressions []kubernetesMatchExpression `yaml:"matchExpressions"`
+}
+
+type kubernetesPreferredTerm struct {
+	Weight     int                        `yaml:"weight"`
+	Preference kubernetesNodeSelectorTerm `yaml:"preference"`
+}
+
+type kubernetesRequiredTerms struct {
+	NodeSelectorTerms []kubernetesNodeSelectorTerm `yaml:"nodeSelectorTerms"`
+}
+
+type kubernetesNodeAffinity struct {
+	Required  *kubernetesRequiredTerms  `yaml:"requiredDuringSchedulingIgnoredDuringExecution,omitempty"`
+	Preferred []kubernetesPreferredTerm `yaml:"preferredDuringSchedulingIgnoredDuringExecution,omitempty"`
+}
+
+// validateKubernetesQualifiedName checks an annotation or label key: an
+// optional DNS subdomain prefix and "/", then a name of up to 63 characters.
+func validateKubernetesQualifiedName(key string) error {
+	name := key
+	if prefix, rest, ok := strings.Cut(key, "/"); ok {
+		if len(prefix) > 253 || !k8sDNSRE.MatchString(prefix) {
+			return fmt.Errorf("key %q has an invalid prefix; it must be a lowercase DNS subdomain", key)
+		}
+		name = rest
+	}
+	if len(name) == 0 || len(name) > 63 || !k8sNameRE.MatchString(name) {
+		return fmt.Errorf("key %q is invalid; names must be 1-63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric", key)
+	}
+	return nil
+}
+
+// ValidateKubernetesAnnotations is the ValidateFunc for `pod_annotations`
+// keys.
+func ValidateKubernetesAnnotations(i interface{}, k string) ([]string, []error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a map", k)}
+	}
+	var errs []error
+	for key := range m {
+		if err := validateKubernetesQualifiedName(key); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", k, err))
+		}
+	}
+	return nil, errs
+}
+
+// ValidateKubernetesLabels checks `pod_node_selector`, whose values must also
+// be valid label values.
+func ValidateKubernetesLabels(i interface{}, k string) ([]string, []error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a map", k)}
+	}
+	var errs []error
+	for key, v := range m {
+		if err := validateKubernetesQualifiedName(key); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", k, err))
+		}
+		if s, _ := v.(string); len(s) > 63 || !k8sValueRE.MatchString(s) {
+			errs = append(errs, fmt.Errorf("%s: value %q of %q is not a valid label value", k, s, key))
+		}
+	}
+	return nil, errs
+}
+
+func tolerationsFromSchema(list []interface{}) ([]kubernetesToleration, error) {
+	out := make([]kubernetesToleration, 0, len(list))
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("could not parse `toleration` entry")
+		}
+		t := kubernetesToleration{
+			Key:      m["key"].(string),
+			Operator: m["operator"].(string),
+			Value:    m["value"].(string),
+			Effect:   m["effect"].(string),
+		}
+		if t.Operator == "Exists" && t.Value != "" {
+			return nil, fmt.Errorf("toleration %d: `value` must be empty when operator is \"Exists\"", i)
+		}
+		if t.Key == "" && t.Operator != "Exists" {
+			return nil, fmt.Errorf("toleration %d: an empty `key` requires operator \"Exists\"", i)
+		}
+		if secs, ok := m["toleration_seconds"].(int); ok && secs != 0 {
+			if t.Effect != "NoExecute" {
+				return nil, fmt.Errorf("toleration %d: `toleration_seconds` only applies to effect \"NoExecute\"", i)
+			}
+			t.TolerationSeconds = &secs
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func matchExpressionsFromSchema(term int, list []interface{}) ([]kubernetesMatchExpression, error) {
+	if len(list) == 0 {
+		return nil, fmt.Errorf("node_affinity_term %d: at least one `match_expression` is required", term)
+	}
+	out := make([]kubernetesMatchExpression, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("could not parse `match_expression` entry")
+		}
+		e := kubernetesMatchExpression{
+			Key:      m["key"].(string),
+			Operator: m["operator"].(string),
+		}
+		for _, v := range m["values"].([]interface{}) {
+			s, _ := v.(string)
+			e.Values = append(e.Values, s)
+		}
+		switch e.Operator {
+		case "In", "NotIn":
+			if len(e.Values) == 0 {
+				return nil, fmt.Errorf("node_affinity_term %d: operator %q on %q requires `values`", term, e.Operator, e.Key)
+			}
+		case "Exists", "DoesNotExist":
+			if len(e.Values) != 0 {
+				return nil, fmt.Errorf("node_affinity_term %d: operator %q on %q must not have `values`", term, e.Operator, e.Key)
+			}
+		case "Gt", "Lt":
+			if len(e.Values) != 1 {
+				return nil, fmt.Errorf("node_affinity_term %d: operator %q on %q requires exactly one value", term, e.Operator, e.Key)
+			}
+			if _, err := strconv.ParseInt(e.Values[0], 10, 64); err != nil {
+				return nil, fmt.Errorf("node_affinity_term %d: operator %q on %q requires an integer value, got %q", term, e.Operator, e.Key, e.Values[0])
+			}
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func nodeAffinityFromSchema(list []interface{}) (*kubernetesNodeAffinity, error) {
+	affinity := &kubernetesNodeAffinity{}
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("could not parse `node_affinity_term` entry")
+		}
+		exprs, err := matchExpressionsFromSchema(i, m["match_expression"].([]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		term := kubernetesNodeSelectorTerm{MatchExpressions: exprs}
+		if weight, _ := m["weight"].(int); weight > 0 {
+			affinity.Preferred = append(affinity.Preferred, kubernetesPreferredTerm{Weight: weight, Preference: term})
+			continue
+		}
+		if affinity.Required == nil {
+			affinity.Required = &kubernetesRequiredTerms{}
+		}
+		affinity.Required.NodeSelectorTerms = append(affinity.Required.NodeSelectorTerms, term)
+	}
+	return affinity, nil
+}
+
+func stringMapFromSchema(v interface{}) map[string]string {
+	raw, _ := v.(map[string]interface{})
+	if len(raw) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(raw))
+	for k, v := range raw {
+		out[k], _ = v.(string)
+	}
+	return out
+}
+
+func marshalYAMLString(v interface{}) (string, error) {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// KubernetesScheduling holds the rendered YAML of the native scheduling
+// blocks. Fields are empty when the corresponding block is not set.
+type KubernetesScheduling struct {
+	Tolerations  string
+	Annotations  string
+	NodeSelector string
+	NodeAffinity string
+}
+
+// KubernetesSchedulingFromSchema validates and renders `toleration`,
+// `pod_annotations`, `pod_node_selector` and `node_affinity_term`.
+func KubernetesSchedulingFromSchema(d SchemaGetter) (KubernetesScheduling, error) {
+	var out KubernetesScheduling
+
+	if list, _ := d.Get("toleration").([]interface{}); len(list) > 0 {
+		tolerations, err := tolerationsFromSchema(list)
+		if err != nil {
+			return out, err
+		}
+		if out.Tolerations, err = marshalYAMLString(tolerations); err != nil {
+			return out, err
+		}
+	}
+
+	if m := stringMapFromSchema(d.Get("pod_annotations")); m != nil {
+		var err error
+		if out.Annotations, err = marshalYAMLString(m); err != nil {
+			return out, err
+		}
+	}
+
+	if m := stringMapFromSchema(d.Get("pod_node_selector")); m != nil {
+		var err error
+		if out.NodeSelector, err = marshalYAMLString(m); err != nil {
+			return out, err
+		}
+	}
+
+	if list, _ := d.Get("node_affinity_term").([]interface{}); len(list) > 0 {
+		affinity, err := nodeAffinityFromSchema(list)
+		if err != nil {
+			return out, err
+		}
+		if out.NodeAffinity, err = marshalYAMLString(affinity); err != nil {
+			return out, err
+		}
+	}
+
+	return out, nil
+}
+
+// SuppressEquivalentYAML ignores differences between two YAML documents that
+// decode to the same value, such as changes in indentation, key order or
+// quoting.
+func SuppressEquivalentYAML(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	var o, n interface{}
+	if err := yaml.Unmarshal([]byte(old), &o); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(new), &n); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(o, n)
+}
+
+// ValidateYAML is the ValidateFunc for the string forms, which previously
+// only failed once pods could not be scheduled.
+func ValidateYAML(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a string", k)}
+	}
+	var out interface{}
+	if err := yaml.Unmarshal([]byte(v), &out); err != nil {
+		return nil, []error{fmt.Errorf("%s is not valid YAML: %w", k, err)}
+	}
+	return nil, nil
+}
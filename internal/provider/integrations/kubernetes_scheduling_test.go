@@ -0,0 +1,143 @@
+package integrations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+func kubernetesSchedulingTestSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"toleration": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"key":                {Type: schema.TypeString, Optional: true},
+					"operator":           {Type: schema.TypeString, Optional: true, Default: "Equal"},
+					"value":              {Type: schema.TypeString, Optional: true},
+					"effect":             {Type: schema.TypeString, Optional: true},
+					"toleration_seconds": {Type: schema.TypeInt, Optional: true},
+				},
+			},
+		},
+		"pod_annotations":   {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"pod_node_selector": {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"node_affinity_term": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"weight": {Type: schema.TypeInt, Optional: true},
+					"match_expression": {
+						Type:     schema.TypeList,
+						Required: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"key":      {Type: schema.TypeString, Required: true},
+								"operator": {Type: schema.TypeString, Required: true},
+								"values":   {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestKubernetesSchedulingFromSchema_Render(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, kubernetesSchedulingTestSchema(), map[string]interface{}{
+		"toleration": []interface{}{
+			map[string]interface{}{"key": "dedicated", "value": "adaptive", "effect": "NoSchedule"},
+			map[string]interface{}{"key": "node.kubernetes.io/unreachable", "operator": "Exists", "effect": "NoExecute", "toleration_seconds": 30},
+		},
+		"pod_annotations":   map[string]interface{}{"sidecar.istio.io/inject": "false"},
+		"pod_node_selector": map[string]interface{}{"kubernetes.io/os": "linux"},
+		"node_affinity_term": []interface{}{
+			map[string]interface{}{"match_expression": []interface{}{
+				map[string]interface{}{"key": "pool", "operator": "In", "values": []interface{}{"tools"}},
+			}},
+			map[string]interface{}{"weight": 10, "match_expression": []interface{}{
+				map[string]interface{}{"key": "zone", "operator": "NotIn", "values": []interface{}{"us-east-1a"}},
+			}},
+		},
+	})
+
+	out, err := KubernetesSchedulingFromSchema(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var tolerations []map[string]interface{}
+	if err := yaml.Unmarshal([]byte(out.Tolerations), &tolerations); err != nil || len(tolerations) != 2 {
+		t.Fatalf("tolerations not rendered as a list: %v\n%s", err, out.Tolerations)
+	}
+	if tolerations[1]["tolerationSeconds"] != 30 || tolerations[0]["operator"] != "Equal" {
+		t.Errorf("unexpected tolerations: %v", tolerations)
+	}
+	if !strings.Contains(out.Annotations, "sidecar.istio.io/inject") || !strings.Contains(out.NodeSelector, "kubernetes.io/os: linux") {
+		t.Errorf("maps not rendered:\n%s\n%s", out.Annotations, out.NodeSelector)
+	}
+	for _, want := range []string{"requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms", "preferredDuringSchedulingIgnoredDuringExecution", "weight: 10"} {
+		if !strings.Contains(out.NodeAffinity, want) {
+			t.Errorf("node affinity missing %q:\n%s", want, out.NodeAffinity)
+		}
+	}
+}
+
+func TestKubernetesSchedulingFromSchema_Invalid(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"exists with value": {"toleration": []interface{}{
+			map[string]interface{}{"key": "a", "operator": "Exists", "value": "b"},
+		}},
+		"empty key with equal": {"toleration": []interface{}{
+			map[string]interface{}{"value": "b"},
+		}},
+		"seconds without NoExecute": {"toleration": []interface{}{
+			map[string]interface{}{"key": "a", "effect": "NoSchedule", "toleration_seconds": 5},
+		}},
+		"in without values": {"node_affinity_term": []interface{}{
+			map[string]interface{}{"match_expression": []interface{}{map[string]interface{}{"key": "pool", "operator": "In"}}},
+		}},
+		"gt with non-integer": {"node_affinity_term": []interface{}{
+			map[string]interface{}{"match_expression": []interface{}{map[string]interface{}{"key": "cpu", "operator": "Gt", "values": []interface{}{"four"}}}},
+		}},
+	}
+
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, kubernetesSchedulingTestSchema(), raw)
+			if _, err := KubernetesSchedulingFromSchema(d); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestValidateKubernetesLabels(t *testing.T) {
+	if _, errs := ValidateKubernetesLabels(map[string]interface{}{"kubernetes.io/os": "linux", "pool": ""}, "pod_node_selector"); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	for _, m := range []map[string]interface{}{
+		{"Bad_Prefix.io/x": "y"},
+		{"pool": "has space"},
+		{"-leading": "y"},
+	} {
+		if _, errs := ValidateKubernetesLabels(m, "pod_node_selector"); len(errs) == 0 {
+			t.Errorf("expected %v to be rejected", m)
+		}
+	}
+}
+
+func TestSuppressEquivalentYAML(t *testing.T) {
+	old := "- key: dedicated\n  operator: Equal\n  value: adaptive\n"
+	if !SuppressEquivalentYAML("tolerations", old, "-   operator: Equal\n    key: dedicated\n    value: \"adaptive\"\n", nil) {
+		t.Error("reformatted YAML should not produce a diff")
+	}
+	if SuppressEquivalentYAML("tolerations", old, "- key: dedicated\n  operator: Exists\n", nil) {
+		t.Error("a semantic change must produce a diff")
+	}
+}
@@ -88,7 +88,7 @@ func resourceAdaptiveMongoUpdate(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "mongodb", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "mongodb", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
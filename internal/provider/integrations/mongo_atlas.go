@@ -96,7 +96,7 @@ func resourceAdaptiveMongoAtlasUpdate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "mongodb_atlas", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "mongodb_atlas", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
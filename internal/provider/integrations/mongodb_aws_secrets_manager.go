@@ -61,8 +61,8 @@ type MongoDBAWSIntegrationConfiguration struct {
 func SchemaToMongoAWSIntegrationConfiguration(d *schema.ResourceData) MongoDBAWSIntegrationConfiguration {
 	return MongoDBAWSIntegrationConfiguration{
 		Name:     d.Get("name").(string),
-		ARN:      d.Get("arn").(string),
-		Region:   d.Get("region").(string),
+		ARN:      CanonicalString(d, "arn"),
+		Region:   CanonicalString(d, "region"),
 		SecretID: d.Get("secret_id").(string),
 		Key:      d.Get("key").(string),
 	}
@@ -105,7 +105,7 @@ func resourceAdaptiveMongoAWSUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "mongodb", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "mongodb", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -96,7 +96,7 @@ func resourceAdaptiveMSTeamsWorkflowUpdate(ctx context.Context, d *schema.Resour
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "msteams_workflow", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "msteams_workflow", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
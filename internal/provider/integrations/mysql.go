@@ -84,23 +84,30 @@ func resourceAdaptiveMySQL() *schema.Resource {
 }
 
 // TODO: .(string) is assumption will cause problems
-func SchemaToMySQLIntegrationConfiguration(d *schema.ResourceData) MySQLIntegrationConfiguration {
+func SchemaToMySQLIntegrationConfiguration(d *schema.ResourceData) (MySQLIntegrationConfiguration, error) {
+	conn, err := SQLConnectionFromSchema(d, "mysql")
+	if err != nil {
+		return MySQLIntegrationConfiguration{}, err
+	}
 	return MySQLIntegrationConfiguration{
 		Version:      "",
 		Name:         d.Get("name").(string),
-		Username:     d.Get("username").(string),
-		Password:     d.Get("password").(string),
-		DatabaseName: d.Get("database_name").(string),
-		HostName:     d.Get("host").(string),
-		Port:         d.Get("port").(string),
+		Username:     conn.Username,
+		Password:     conn.Password,
+		DatabaseName: conn.DatabaseName,
+		HostName:     conn.Host,
+		Port:         conn.Port,
 		SSLMode:      "require",
-	}
+	}, nil
 }
 
 func resourceAdaptiveMySQLCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*adaptive.Client)
 
-	obj := SchemaToMySQLIntegrationConfiguration(d)
+	obj, err := SchemaToMySQLIntegrationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	config, err := yaml.Marshal(obj)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
@@ -128,13 +135,16 @@ func resourceAdaptiveMySQLUpdate(ctx context.Context, d *schema.ResourceData, m
 	client := m.(*adaptive.Client)
 	resourceID := d.Id()
 
-	obj := SchemaToMySQLIntegrationConfiguration(d)
+	obj, err := SchemaToMySQLIntegrationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	config, err := yaml.Marshal(obj)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "mysql", config, []string{}, "")
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "mysql", config, []string{}, "")
 	if err != nil {
 		return diag.FromErr(err)
 	}
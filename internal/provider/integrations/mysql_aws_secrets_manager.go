@@ -70,8 +70,8 @@ func SchemaToMySQLAWSIntegrationConfiguration(d *schema.ResourceData) MySQLAWSIn
 	return MySQLAWSIntegrationConfiguration{
 		Version:  "",
 		Name:     d.Get("name").(string),
-		ARN:      d.Get("arn").(string),
-		Region:   d.Get("region").(string),
+		ARN:      CanonicalString(d, "arn"),
+		Region:   CanonicalString(d, "region"),
 		SecretID: d.Get("secret_id").(string),
 	}
 }
@@ -134,7 +134,7 @@ func resourceAdaptiveMySQLAWSUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "mysql", config, userTags, defaultCluster)
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "mysql", config, userTags, defaultCluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
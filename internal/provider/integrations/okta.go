@@ -135,7 +135,7 @@ func resourceAdaptiveOktaUpdate(ctx context.Context, d *schema.ResourceData, m i
 		return diag.FromErr(err)
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "okta", config, userTags, defaultCluster)
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "okta", config, userTags, defaultCluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -14,9 +14,9 @@ type PaloAltoNGFWIntegrationConfiguration struct {
 func SchemaToPaloAltoNGFWIntegrationConfiguration(d *schema.ResourceData) PaloAltoNGFWIntegrationConfiguration {
 	return PaloAltoNGFWIntegrationConfiguration{
 		Name:      d.Get("name").(string),
-		Password:  d.Get("password").(string),
+		Password:  CanonicalString(d, "password"),
 		Username:  d.Get("username").(string),
-		Hostname:  d.Get("hostname").(string),
+		Hostname:  CanonicalString(d, "host"),
 		WebuiPort: d.Get("webui_port").(string),
 		LoginUrl:  d.Get("login_url").(string),
 	}
@@ -107,14 +107,10 @@ type PostgresIntegrationConfiguration struct {
 }
 
 // TODO: .(string) is assumption will cause problems
-func SchemaToPostgresIntegrationConfiguration(d *schema.ResourceData) PostgresIntegrationConfiguration {
-	sslMode := ""
-	if d.Get("ssl_mode") != nil {
-		if _sslMode, ok := d.Get("ssl_mode").(string); ok {
-			sslMode = _sslMode
-		} else if !ok {
-			sslMode = ""
-		}
+func SchemaToPostgresIntegrationConfiguration(d *schema.ResourceData) (PostgresIntegrationConfiguration, error) {
+	conn, err := SQLConnectionFromSchema(d, "postgres")
+	if err != nil {
+		return PostgresIntegrationConfiguration{}, err
 	}
 
 	tlsRootCert := ""
@@ -140,22 +136,25 @@ func SchemaToPostgresIntegrationConfiguration(d *schema.ResourceData) PostgresIn
 
 	return PostgresIntegrationConfiguration{
 		Name:         d.Get("name").(string),
-		Username:     d.Get("username").(string),
-		Password:     d.Get("password").(string),
-		DatabaseName: d.Get("database_name").(string),
-		HostName:     d.Get("host").(string),
-		Port:         d.Get("port").(string),
-		SSLMode:      sslMode,
+		Username:     conn.Username,
+		Password:     conn.Password,
+		DatabaseName: conn.DatabaseName,
+		HostName:     conn.Host,
+		Port:         conn.Port,
+		SSLMode:      conn.SSLMode,
 		TLSRootCert:  tlsRootCert,
 		TLSCertFile:  tlsCertFile,
 		TLSKeyFile:   tlsKeyFile,
-	}
+	}, nil
 }
 
 func resourceAdaptivePostgresCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*adaptive.Client)
 
-	obj := SchemaToPostgresIntegrationConfiguration(d)
+	obj, err := SchemaToPostgresIntegrationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	config, err := yaml.Marshal(obj)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
@@ -193,7 +192,10 @@ func resourceAdaptivePostgresUpdate(ctx context.Context, d *schema.ResourceData,
 	client := m.(*adaptive.Client)
 	resourceID := d.Id()
 
-	obj := SchemaToPostgresIntegrationConfiguration(d)
+	obj, err := SchemaToPostgresIntegrationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	config, err := yaml.Marshal(obj)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
@@ -209,7 +211,7 @@ func resourceAdaptivePostgresUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "postgres", config, userTags, defaultCluster)
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "postgres", config, userTags, defaultCluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -68,8 +68,8 @@ type PostgresIntegrationAWSConfiguration struct {
 func SchemaToPostgresAWSIntegrationConfiguration(d *schema.ResourceData) PostgresIntegrationAWSConfiguration {
 	return PostgresIntegrationAWSConfiguration{
 		Name:     d.Get("name").(string),
-		ARN:      d.Get("arn").(string),
-		Region:   d.Get("region").(string),
+		ARN:      CanonicalString(d, "arn"),
+		Region:   CanonicalString(d, "region"),
 		SecretID: d.Get("secret_id").(string),
 	}
 }
@@ -132,7 +132,7 @@ func resourceAdaptivePostgresAWSUpdate(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "postgres", config, userTags, defaultCluster)
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "postgres", config, userTags, defaultCluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -14,6 +14,6 @@ func SchemaToRabbitMQIntegrationConfiguration(d *schema.ResourceData) RabbitMQIn
 		Url:      d.Get("uri").(string),
 		Name:     d.Get("name").(string),
 		Username: d.Get("username").(string),
-		Password: d.Get("password").(string),
+		Password: CanonicalString(d, "password"),
 	}
 }
@@ -2,17 +2,41 @@ package integrations
 
 import (
 	"errors"
+	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
 )
 
+// defaultServerListPort is used for hosts that do not name a port.
+const defaultServerListPort = 22
+
 type ServerListIntegrationConfiguration struct {
 	Version     string `yaml:"version"`
 	Hosts       string `yaml:"hosts"`
 	DefaultUser string `yaml:"user"`
 	SshKey      string `yaml:"sshKey"`
 	Password    string `yaml:"password"`
+	// Servers is a YAML-encoded list of ServerListHostConfig carrying the
+	// per-host settings of `server` blocks. Hosts still lists every server
+	// so the address list stays complete for consumers that ignore it.
+	Servers       string              `yaml:"servers,omitempty"`
+	KnownHostKeys string              `yaml:"knownHostKeys,omitempty"`
+	JumpHosts     []SSHJumpHostConfig `yaml:"jumpHosts,omitempty"`
+}
+
+// ServerListHostConfig is one `server` block. Empty User, SshKey and Password
+// fall back to the resource-wide default_user, key and password.
+type ServerListHostConfig struct {
+	Address  string            `yaml:"address"`
+	Port     int               `yaml:"port"`
+	User     string            `yaml:"user,omitempty"`
+	SshKey   string            `yaml:"sshKey,omitempty"`
+	Password string            `yaml:"password,omitempty"`
+	Labels   map[string]string `yaml:"labels,omitempty"`
 }
 
 func SchemaToServerListIntegrationConfiguration(d *schema.ResourceData) (ServerListIntegrationConfiguration, error) {
@@ -29,8 +53,34 @@ func SchemaToServerListIntegrationConfiguration(d *schema.ResourceData) (ServerL
 		}
 	}
 
+	serverBlocks, _ := d.Get("server").([]interface{})
+	servers, err := serverListHostsFromSchema(serverBlocks)
+	if err != nil {
+		return ServerListIntegrationConfiguration{}, err
+	}
+	if err := CheckServerListHosts(hosts, serverBlocks); err != nil {
+		return ServerListIntegrationConfiguration{}, err
+	}
+
+	var serversYAML string
+	if len(servers) > 0 {
+		for _, s := range servers {
+			hosts = append(hosts, net.JoinHostPort(s.Address, strconv.Itoa(s.Port)))
+		}
+		out, err := yaml.Marshal(servers)
+		if err != nil {
+			return ServerListIntegrationConfiguration{}, fmt.Errorf("could not marshal `server` blocks: %w", err)
+		}
+		serversYAML = string(out)
+	}
+
 	hostsNSV := strings.Join(hosts, "\n")
 
+	knownHostKeys, jumpHosts, err := SSHHostVerificationFromSchema(d)
+	if err != nil {
+		return ServerListIntegrationConfiguration{}, err
+	}
+
 	var sshKey string
 	if v, ok := d.GetOk("key"); ok {
 		sshKey = v.(string)
@@ -47,10 +97,165 @@ func SchemaToServerListIntegrationConfiguration(d *schema.ResourceData) (ServerL
 	}
 
 	return ServerListIntegrationConfiguration{
-		Version:     "1",
-		Hosts:       hostsNSV,
-		SshKey:      sshKey,
-		Password:    password,
-		DefaultUser: defaultUser,
+		Version:       "1",
+		Hosts:         hostsNSV,
+		SshKey:        sshKey,
+		Password:      password,
+		DefaultUser:   defaultUser,
+		Servers:       serversYAML,
+		KnownHostKeys: knownHostKeys,
+		JumpHosts:     jumpHosts,
 	}, nil
 }
+
+func serverListHostsFromSchema(list []interface{}) ([]ServerListHostConfig, error) {
+	servers := make([]ServerListHostConfig, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("could not parse `server` entry")
+		}
+		var labels map[string]string
+		if raw, ok := m["labels"].(map[string]interface{}); ok && len(raw) > 0 {
+			labels = make(map[string]string, len(raw))
+			for k, v := range raw {
+				labels[k] = v.(string)
+			}
+		}
+		servers = append(servers, ServerListHostConfig{
+			Address:  m["address"].(string),
+			Port:     m["port"].(int),
+			User:     m["username"].(string),
+			SshKey:   m["key"].(string),
+			Password: m["password"].(string),
+			Labels:   labels,
+		})
+	}
+	return servers, nil
+}
+
+// parseServerListHost splits a `hosts` entry of the form host, host:port or
+// [ipv6]:port. A missing port is reported as defaultServerListPort.
+func parseServerListHost(entry string) (host string, port int, err error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return "", 0, errors.New("host must not be empty")
+	}
+
+	// Bare host names and unbracketed IPv6 addresses carry no port.
+	if !strings.Contains(entry, ":") || (!strings.HasPrefix(entry, "[") && net.ParseIP(entry) != nil) {
+		if strings.ContainsAny(entry, " /@") {
+			return "", 0, fmt.Errorf("%q is not a valid host", entry)
+		}
+		return entry, defaultServerListPort, nil
+	}
+
+	host, portStr, err := net.SplitHostPort(entry)
+	if err != nil {
+		return "", 0, fmt.Errorf("%q is not a valid host or host:port", entry)
+	}
+	if host == "" || strings.ContainsAny(host, " /@") {
+		return "", 0, fmt.Errorf("%q is not a valid host or host:port", entry)
+	}
+	port, err = strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("%q has an invalid port; expected a number between 1 and 65535", entry)
+	}
+	return host, port, nil
+}
+
+// parseServerListHostsEntry parses an entry of `hosts`, which unlike a
+// `server` address may name its user as user@host, as entries could before
+// hosts were validated.
+func parseServerListHostsEntry(entry string) (user, host string, port int, err error) {
+	entry = strings.TrimSpace(entry)
+	if i := strings.LastIndex(entry, "@"); i >= 0 {
+		user, entry = entry[:i], entry[i+1:]
+		if user == "" || strings.ContainsAny(user, " /:") {
+			return "", "", 0, fmt.Errorf("%q is not a valid user@host", user+"@"+entry)
+		}
+	}
+	host, port, err = parseServerListHost(entry)
+	return user, host, port, err
+}
+
+// ValidateServerListHost is the ValidateFunc for entries of `hosts`.
+func ValidateServerListHost(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a string", k)}
+	}
+	if _, _, _, err := parseServerListHostsEntry(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// ValidateServerListAddress is the ValidateFunc for `server` block addresses,
+// which name the port separately.
+func ValidateServerListAddress(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a string", k)}
+	}
+	host, _, err := parseServerListHost(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	if host != strings.Trim(strings.TrimSpace(v), "[]") {
+		return nil, []error{fmt.Errorf("%s: %q must not include a port; set `port` instead", k, v)}
+	}
+	return nil, nil
+}
+
+// CheckServerListHosts rejects a host that appears more than once across the
+// flat `hosts` list and the `server` blocks. Hosts compare case-insensitively
+// and with the default port filled in, so "db1" and "DB1:22" collide. A
+// `hosts` entry naming its user only collides with the same user@host, so
+// logins as different users to one host stay valid. Entries that are still
+// unknown at plan time arrive empty and are skipped.
+func CheckServerListHosts(hosts []string, servers []interface{}) error {
+	seen := make(map[string]bool, len(hosts)+len(servers))
+	check := func(user, host string, port int) error {
+		key := net.JoinHostPort(strings.ToLower(host), strconv.Itoa(port))
+		if user != "" {
+			key = user + "@" + key
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate serverlist host %s: each host must appear only once across `hosts` and `server` blocks", key)
+		}
+		seen[key] = true
+		return nil
+	}
+
+	for _, entry := range hosts {
+		if entry == "" {
+			continue
+		}
+		user, host, port, err := parseServerListHostsEntry(entry)
+		if err != nil {
+			return err
+		}
+		if err := check(user, host, port); err != nil {
+			return err
+		}
+	}
+	for _, item := range servers {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		address, _ := m["address"].(string)
+		port, _ := m["port"].(int)
+		if address == "" {
+			continue
+		}
+		if port == 0 {
+			port = defaultServerListPort
+		}
+		if err := check("", address, port); err != nil {
+			return err
+		}
+	}
+	return nil
+}
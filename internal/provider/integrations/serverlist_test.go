@@ -0,0 +1,140 @@
+package integrations
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+func serverListTestSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"hosts":        {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"default_user": {Type: schema.TypeString, Optional: true},
+		"key":          {Type: schema.TypeString, Optional: true},
+		"password":     {Type: schema.TypeString, Optional: true},
+		"server": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"address":  {Type: schema.TypeString, Required: true},
+					"port":     {Type: schema.TypeInt, Optional: true, Default: 22},
+					"username": {Type: schema.TypeString, Optional: true},
+					"key":      {Type: schema.TypeString, Optional: true},
+					"password": {Type: schema.TypeString, Optional: true},
+					"labels":   {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				},
+			},
+		},
+	}
+}
+
+func TestParseServerListHost(t *testing.T) {
+	tests := []struct {
+		entry    string
+		wantHost string
+		wantPort int
+		wantErr  bool
+	}{
+		{entry: "db1.internal", wantHost: "db1.internal", wantPort: 22},
+		{entry: "db1.internal:2222", wantHost: "db1.internal", wantPort: 2222},
+		{entry: "10.0.0.5:22", wantHost: "10.0.0.5", wantPort: 22},
+		{entry: "fe80::1", wantHost: "fe80::1", wantPort: 22},
+		{entry: "[fe80::1]:2200", wantHost: "fe80::1", wantPort: 2200},
+		{entry: "", wantErr: true},
+		{entry: "db1:", wantErr: true},
+		{entry: "db1:ssh", wantErr: true},
+		{entry: "db1:70000", wantErr: true},
+		{entry: ":22", wantErr: true},
+		{entry: "db1:22:33", wantErr: true},
+		{entry: "root@db1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		host, port, err := parseServerListHost(tt.entry)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseServerListHost(%q) error = %v, wantErr %v", tt.entry, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (host != tt.wantHost || port != tt.wantPort) {
+			t.Errorf("parseServerListHost(%q) = (%q, %d), want (%q, %d)", tt.entry, host, port, tt.wantHost, tt.wantPort)
+		}
+	}
+}
+
+// `hosts` entries were not validated before, so user@host entries written
+// then must keep planning.
+func TestParseServerListHostsEntry(t *testing.T) {
+	for entry, want := range map[string]string{
+		"root@db1":           "root db1 22",
+		"deploy@db1:2222":    "deploy db1 2222",
+		"ops@[fe80::1]:2200": "ops fe80::1 2200",
+		"db1":                " db1 22",
+	} {
+		user, host, port, err := parseServerListHostsEntry(entry)
+		if got := fmt.Sprintf("%s %s %d", user, host, port); err != nil || got != want {
+			t.Errorf("parseServerListHostsEntry(%q) = %q, %v; want %q", entry, got, err, want)
+		}
+	}
+	for _, entry := range []string{"@db1", "a b@db1", "root@", "root@db1/x"} {
+		if _, _, _, err := parseServerListHostsEntry(entry); err == nil {
+			t.Errorf("parseServerListHostsEntry(%q) accepted", entry)
+		}
+	}
+}
+
+func TestCheckServerListHosts_Duplicates(t *testing.T) {
+	if err := CheckServerListHosts([]string{"db1", "DB1:22"}, nil); err == nil {
+		t.Error("expected default-port duplicate in hosts to be rejected")
+	}
+	servers := []interface{}{map[string]interface{}{"address": "db1", "port": 22}}
+	if err := CheckServerListHosts([]string{"db1"}, servers); err == nil {
+		t.Error("expected duplicate across hosts and server blocks to be rejected")
+	}
+	if err := CheckServerListHosts([]string{"db1:2222", ""}, servers); err != nil {
+		t.Errorf("distinct ports must not collide: %v", err)
+	}
+	if err := CheckServerListHosts([]string{"alice@db1", "bob@db1"}, nil); err != nil {
+		t.Errorf("logins as different users must not collide: %v", err)
+	}
+	if err := CheckServerListHosts([]string{"alice@db1", "alice@DB1:22"}, nil); err == nil {
+		t.Error("expected a repeated user@host to be rejected")
+	}
+}
+
+func TestSchemaToServerListIntegrationConfiguration_ServerBlocks(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, serverListTestSchema(), map[string]interface{}{
+		"hosts":        []interface{}{"legacy.internal"},
+		"default_user": "ubuntu",
+		"server": []interface{}{
+			map[string]interface{}{
+				"address":  "db1.internal",
+				"port":     2222,
+				"username": "postgres",
+				"labels":   map[string]interface{}{"role": "db"},
+			},
+		},
+	})
+
+	cfg, err := SchemaToServerListIntegrationConfiguration(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Hosts != "legacy.internal\ndb1.internal:2222" {
+		t.Errorf("hosts = %q", cfg.Hosts)
+	}
+
+	var servers []ServerListHostConfig
+	if err := yaml.Unmarshal([]byte(cfg.Servers), &servers); err != nil {
+		t.Fatalf("servers is not valid YAML: %v", err)
+	}
+	if len(servers) != 1 || servers[0].User != "postgres" || servers[0].Port != 2222 || servers[0].Labels["role"] != "db" {
+		t.Errorf("unexpected servers: %+v", servers)
+	}
+	if strings.Contains(cfg.Servers, "password") {
+		t.Errorf("unset password override must be omitted:\n%s", cfg.Servers)
+	}
+}
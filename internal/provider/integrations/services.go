@@ -116,7 +116,7 @@ func resourceAdaptiveServiceListUpdate(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "servicelist", config, userTags, defaultCluster)
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "servicelist", config, userTags, defaultCluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
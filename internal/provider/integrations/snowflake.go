@@ -17,9 +17,9 @@ type SnowflakeIntegrationConfiguration struct {
 func SchemaToSnowflakeIntegrationConfiguration(d *schema.ResourceData) SnowflakeIntegrationConfiguration {
 	return SnowflakeIntegrationConfiguration{
 		Name:             d.Get("name").(string),
-		DatabaseAccount:  d.Get("hostname").(string),
+		DatabaseAccount:  CanonicalString(d, "host"),
 		DatabaseUsername: d.Get("username").(string),
-		DatabasePassword: d.Get("password").(string),
+		DatabasePassword: CanonicalString(d, "password"),
 		DatabaseName:     d.Get("database_name").(string),
 		Warehouse:        d.Get("warehouse").(string),
 		Schema:           d.Get("schema").(string),
@@ -12,8 +12,8 @@ type SnowflakeAWSIntegrationConfiguration struct {
 func SchemaToSnowflakeAWSIntegrationConfiguration(d *schema.ResourceData) SnowflakeAWSIntegrationConfiguration {
 	return SnowflakeAWSIntegrationConfiguration{
 		Name:     d.Get("name").(string),
-		ARN:      d.Get("arn").(string),
-		Region:   d.Get("region").(string),
+		ARN:      CanonicalString(d, "arn"),
+		Region:   CanonicalString(d, "region"),
 		SecretID: d.Get("secret_id").(string),
 	}
 }
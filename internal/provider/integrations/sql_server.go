@@ -11,13 +11,17 @@ type SQLServerIntegrationConfiguration struct {
 	Password     string `yaml:"password"`
 }
 
-func SchemaToSQLServerIntegrationConfiguration(d *schema.ResourceData) SQLServerIntegrationConfiguration {
+func SchemaToSQLServerIntegrationConfiguration(d *schema.ResourceData) (SQLServerIntegrationConfiguration, error) {
+	conn, err := SQLConnectionFromSchema(d, "sql_server")
+	if err != nil {
+		return SQLServerIntegrationConfiguration{}, err
+	}
 	return SQLServerIntegrationConfiguration{
 		Name:         d.Get("name").(string),
-		DatabaseName: d.Get("database_name").(string),
-		Hostname:     d.Get("host").(string),
-		Port:         d.Get("port").(string),
-		Username:     d.Get("username").(string),
-		Password:     d.Get("password").(string),
-	}
+		DatabaseName: conn.DatabaseName,
+		Hostname:     conn.Host,
+		Port:         conn.Port,
+		Username:     conn.Username,
+		Password:     conn.Password,
+	}, nil
 }
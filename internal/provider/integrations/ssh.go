@@ -14,24 +14,48 @@ Example resource usage:
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	adaptive "github.com/adaptive-scale/terraform-provider-adaptive/internal/terraform-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
 	"gopkg.in/yaml.v2"
 )
 
+// SSH authentication methods accepted by `auth_method`.
+const (
+	SSHAuthPassword                 = "password"
+	SSHAuthPrivateKey               = "private_key"
+	SSHAuthPrivateKeyWithPassphrase = "private_key_with_passphrase"
+	SSHAuthCertificate              = "ssh_certificate"
+)
+
+var SSHAuthMethods = []string{
+	SSHAuthPassword,
+	SSHAuthPrivateKey,
+	SSHAuthPrivateKeyWithPassphrase,
+	SSHAuthCertificate,
+}
+
 type SSHIntegrationConfiguration struct {
-	Version     string `yaml:"version"`
-	Name        string `yaml:"name"`
-	Username    string `yaml:"username"`
-	UsePassword bool   `yaml:"usePassword"`
-	Password    string `yaml:"password"`
-	HostName    string `yaml:"hostname"`
-	Port        string `yaml:"port"`
-	SSHKey      string `yaml:"sshKey"`
+	Version       string `yaml:"version"`
+	Name          string `yaml:"name"`
+	Username      string `yaml:"username"`
+	AuthMethod    string `yaml:"authMethod"`
+	UsePassword   bool   `yaml:"usePassword"`
+	Password      string `yaml:"password"`
+	HostName      string `yaml:"hostname"`
+	Port          string `yaml:"port"`
+	SSHKey        string `yaml:"sshKey"`
+	KeyPassphrase string `yaml:"keyPassphrase,omitempty"`
+	Certificate   string `yaml:"certificate,omitempty"`
+	// KnownHostKeys holds known_hosts lines; when set the agent refuses
+	// host keys that do not match.
+	KnownHostKeys string              `yaml:"knownHostKeys,omitempty"`
+	JumpHosts     []SSHJumpHostConfig `yaml:"jumpHosts,omitempty"`
 }
 
 func resourceAdaptiveSSH() *schema.Resource {
@@ -82,23 +106,144 @@ func resourceAdaptiveSSH() *schema.Resource {
 	}
 }
 
-func SchemaToSSHIntegrationConfiguration(d *schema.ResourceData) SSHIntegrationConfiguration {
-	return SSHIntegrationConfiguration{
-		Version:     "1.0",
-		Name:        d.Get("name").(string),
-		Username:    d.Get("username").(string),
-		UsePassword: d.Get("key").(string) == "",
-		Password:    d.Get("key").(string),
-		HostName:    d.Get("host").(string),
-		Port:        d.Get("port").(string),
-		SSHKey:      d.Get("key").(string),
+func SchemaToSSHIntegrationConfiguration(d *schema.ResourceData) (SSHIntegrationConfiguration, error) {
+	authMethod, _ := d.Get("auth_method").(string)
+	password := CanonicalString(d, "password")
+	key, _ := d.Get("key").(string)
+	passphrase, _ := d.Get("key_passphrase").(string)
+	certificate, _ := d.Get("ssh_certificate").(string)
+
+	authMethod = ResolveSSHAuthMethod(authMethod, key, passphrase)
+	if err := ValidateSSHAuth(authMethod, password, key, passphrase, certificate); err != nil {
+		return SSHIntegrationConfiguration{}, err
+	}
+
+	knownHostKeys, jumpHosts, err := SSHHostVerificationFromSchema(d)
+	if err != nil {
+		return SSHIntegrationConfiguration{}, err
+	}
+
+	cfg := SSHIntegrationConfiguration{
+		Version:       "1.0",
+		KnownHostKeys: knownHostKeys,
+		JumpHosts:     jumpHosts,
+		Name:          d.Get("name").(string),
+		Username:      d.Get("username").(string),
+		AuthMethod:    authMethod,
+		HostName:      CanonicalString(d, "host"),
+		Port:          d.Get("port").(string),
+	}
+	// Only send the credentials the chosen method uses, so a leftover key
+	// can't shadow a password or the other way round.
+	switch authMethod {
+	case SSHAuthPassword:
+		cfg.UsePassword = true
+		cfg.Password = password
+	case SSHAuthPrivateKey:
+		cfg.SSHKey = key
+	case SSHAuthPrivateKeyWithPassphrase:
+		cfg.SSHKey = key
+		cfg.KeyPassphrase = passphrase
+	case SSHAuthCertificate:
+		cfg.SSHKey = key
+		cfg.KeyPassphrase = passphrase
+		cfg.Certificate = certificate
+	}
+	return cfg, nil
+}
+
+// ResolveSSHAuthMethod returns the configured auth method, or infers one from
+// the credentials when it is unset: a key means key auth, otherwise password.
+func ResolveSSHAuthMethod(authMethod, key, passphrase string) string {
+	if authMethod != "" {
+		return authMethod
+	}
+	switch {
+	case key != "" && passphrase != "":
+		return SSHAuthPrivateKeyWithPassphrase
+	case key != "":
+		return SSHAuthPrivateKey
+	default:
+		return SSHAuthPassword
 	}
 }
 
+// ValidateSSHAuth checks that the credentials needed by authMethod are present
+// and that keys and certificates parse, so mistakes surface at plan time
+// rather than as a failed connection.
+func ValidateSSHAuth(authMethod, password, key, passphrase, certificate string) error {
+	switch authMethod {
+	case SSHAuthPassword:
+		if password == "" {
+			return errors.New("auth_method \"password\" requires a non-empty `password`")
+		}
+		return nil
+	case SSHAuthPrivateKey:
+		_, err := parseSSHPrivateKey(key, "")
+		return err
+	case SSHAuthPrivateKeyWithPassphrase:
+		if passphrase == "" {
+			return errors.New("auth_method \"private_key_with_passphrase\" requires `key_passphrase`")
+		}
+		_, err := parseSSHPrivateKey(key, passphrase)
+		return err
+	case SSHAuthCertificate:
+		signer, err := parseSSHPrivateKey(key, passphrase)
+		if err != nil {
+			return err
+		}
+		if certificate == "" {
+			return errors.New("auth_method \"ssh_certificate\" requires `ssh_certificate`")
+		}
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(certificate))
+		if err != nil {
+			return fmt.Errorf("`ssh_certificate` could not be parsed: %w", err)
+		}
+		cert, ok := pub.(*ssh.Certificate)
+		if !ok {
+			return errors.New("`ssh_certificate` is a public key, not an OpenSSH certificate")
+		}
+		if cert.CertType != ssh.UserCert {
+			return errors.New("`ssh_certificate` must be a user certificate, not a host certificate")
+		}
+		if string(cert.Key.Marshal()) != string(signer.PublicKey().Marshal()) {
+			return errors.New("`ssh_certificate` was not issued for `key`")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported auth_method %q", authMethod)
+	}
+}
+
+func parseSSHPrivateKey(key, passphrase string) (ssh.Signer, error) {
+	if key == "" {
+		return nil, errors.New("`key` is required for key-based SSH authentication")
+	}
+	if passphrase != "" {
+		signer, err := ssh.ParsePrivateKeyWithPassphrase([]byte(key), []byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("`key` could not be decrypted with `key_passphrase`: %w", err)
+		}
+		return signer, nil
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(key))
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return nil, errors.New("`key` is encrypted; set `key_passphrase` and auth_method \"private_key_with_passphrase\"")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("`key` is not a valid PEM or OpenSSH private key: %w", err)
+	}
+	return signer, nil
+}
+
 func resourceAdaptiveSSHCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*adaptive.Client)
 
-	obj := SchemaToSSHIntegrationConfiguration(d)
+	obj, err := SchemaToSSHIntegrationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	config, err := yaml.Marshal(obj)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
@@ -137,7 +282,10 @@ func resourceAdaptiveSSHUpdate(ctx context.Context, d *schema.ResourceData, m in
 	client := m.(*adaptive.Client)
 	resourceID := d.Id()
 
-	obj := SchemaToSSHIntegrationConfiguration(d)
+	obj, err := SchemaToSSHIntegrationConfiguration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	config, err := yaml.Marshal(obj)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("provider error, could not marshal: %w", err))
@@ -152,7 +300,7 @@ func resourceAdaptiveSSHUpdate(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(err)
 	}
 
-	_, err = client.UpdateResource(ctx, resourceID, "ssh", config, userTags, defaultCluster)
+	_, err = client.UpdateResource(ctx, resourceID, d.Get("name").(string), "ssh", config, userTags, defaultCluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -0,0 +1,111 @@
+package integrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHJumpHostConfig is one bastion hop. Hops are dialled in order, each
+// through the previous one, before connecting to the target.
+type SSHJumpHostConfig struct {
+	Host          string `yaml:"host"`
+	Port          int    `yaml:"port"`
+	Username      string `yaml:"username"`
+	SSHKey        string `yaml:"sshKey"`
+	KnownHostKeys string `yaml:"knownHostKeys,omitempty"`
+}
+
+// parseKnownHosts checks that s holds at least one OpenSSH known_hosts entry
+// and that every non-comment line parses.
+func parseKnownHosts(s string) error {
+	in := []byte(s)
+	n := 0
+	for {
+		_, _, _, _, rest, err := ssh.ParseKnownHosts(in)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("not a valid known_hosts entry: %w", err)
+		}
+		n++
+		in = rest
+	}
+	if n == 0 {
+		return errors.New("no known_hosts entry found")
+	}
+	return nil
+}
+
+// ValidateKnownHostKeys is the ValidateFunc for `known_host_keys` entries. An
+// entry may hold several lines, e.g. the contents of a known_hosts file.
+func ValidateKnownHostKeys(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a string", k)}
+	}
+	if err := parseKnownHosts(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+func knownHostKeysFromList(list []interface{}) string {
+	lines := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+			lines = append(lines, strings.TrimSpace(s))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// SSHHostVerificationFromSchema reads `known_host_keys` and the `jump_host`
+// chain shared by the SSH and serverlist integrations.
+func SSHHostVerificationFromSchema(d *schema.ResourceData) (knownHostKeys string, jumpHosts []SSHJumpHostConfig, err error) {
+	if list, ok := d.Get("known_host_keys").([]interface{}); ok {
+		knownHostKeys = knownHostKeysFromList(list)
+	}
+
+	hops, _ := d.Get("jump_host").([]interface{})
+	for i, item := range hops {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return "", nil, errors.New("could not parse `jump_host` entry")
+		}
+		hop := SSHJumpHostConfig{
+			Host:     m["host"].(string),
+			Port:     m["port"].(int),
+			Username: m["username"].(string),
+			SSHKey:   m["key"].(string),
+		}
+		if keys, ok := m["known_host_keys"].([]interface{}); ok {
+			hop.KnownHostKeys = knownHostKeysFromList(keys)
+		}
+		if _, err := parseSSHPrivateKey(hop.SSHKey, ""); err != nil {
+			return "", nil, fmt.Errorf("jump_host %d (%s): %w", i, hop.Host, err)
+		}
+		jumpHosts = append(jumpHosts, hop)
+	}
+	return knownHostKeys, jumpHosts, nil
+}
+
+// ValidateSSHPrivateKey is the ValidateFunc for unencrypted private keys.
+func ValidateSSHPrivateKey(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a string", k)}
+	}
+	if _, err := parseSSHPrivateKey(v, ""); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
@@ -0,0 +1,203 @@
+package integrations
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
+)
+
+func sshTestSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name":            {Type: schema.TypeString, Optional: true},
+		"username":        {Type: schema.TypeString, Optional: true},
+		"host":            {Type: schema.TypeString, Optional: true},
+		"port":            {Type: schema.TypeString, Optional: true},
+		"password":        {Type: schema.TypeString, Optional: true},
+		"key":             {Type: schema.TypeString, Optional: true},
+		"auth_method":     {Type: schema.TypeString, Optional: true},
+		"key_passphrase":  {Type: schema.TypeString, Optional: true},
+		"ssh_certificate": {Type: schema.TypeString, Optional: true},
+	}
+}
+
+// testSSHKey returns an OpenSSH private key, the same key encrypted with
+// passphrase, and a user certificate for it signed by a throwaway CA.
+func testSSHKey(t *testing.T, passphrase string) (plain, encrypted, cert string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encBlock, err := ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, caPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caSigner, err := ssh.NewSignerFromKey(caPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ssh.Certificate{
+		Key:             sshPub,
+		CertType:        ssh.UserCert,
+		ValidPrincipals: []string{"deploy"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := c.SignCert(rand.Reader, caSigner); err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(block)), string(pem.EncodeToMemory(encBlock)), string(ssh.MarshalAuthorizedKey(c))
+}
+
+func TestValidateSSHAuth(t *testing.T) {
+	key, encKey, cert := testSSHKey(t, "s3cret")
+	_, _, otherCert := testSSHKey(t, "s3cret")
+
+	tests := []struct {
+		name                                           string
+		method, password, key, passphrase, certificate string
+		wantErr                                        string
+	}{
+		{name: "password", method: SSHAuthPassword, password: "pw"},
+		{name: "empty password", method: SSHAuthPassword, wantErr: "password"},
+		{name: "private key", method: SSHAuthPrivateKey, key: key},
+		{name: "garbage key", method: SSHAuthPrivateKey, key: "not a key", wantErr: "not a valid"},
+		{name: "encrypted key without passphrase", method: SSHAuthPrivateKey, key: encKey, wantErr: "encrypted"},
+		{name: "encrypted key", method: SSHAuthPrivateKeyWithPassphrase, key: encKey, passphrase: "s3cret"},
+		{name: "wrong passphrase", method: SSHAuthPrivateKeyWithPassphrase, key: encKey, passphrase: "nope", wantErr: "decrypted"},
+		{name: "missing passphrase", method: SSHAuthPrivateKeyWithPassphrase, key: encKey, wantErr: "key_passphrase"},
+		{name: "certificate", method: SSHAuthCertificate, key: key, certificate: cert},
+		{name: "certificate for another key", method: SSHAuthCertificate, key: key, certificate: otherCert, wantErr: "not issued"},
+		{name: "public key instead of certificate", method: SSHAuthCertificate, key: key, certificate: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl", wantErr: "not an OpenSSH certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSSHAuth(tt.method, tt.password, tt.key, tt.passphrase, tt.certificate)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// Password auth used to send the (empty) key as the password. Each mode must
+// now send only its own credentials.
+func TestSchemaToSSHIntegrationConfiguration_AuthFields(t *testing.T) {
+	key, _, _ := testSSHKey(t, "s3cret")
+
+	d := schema.TestResourceDataRaw(t, sshTestSchema(), map[string]interface{}{
+		"name":     "bastion",
+		"username": "deploy",
+		"host":     "bastion.internal",
+		"port":     "22",
+		"password": "pw",
+	})
+	cfg, err := SchemaToSSHIntegrationConfiguration(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AuthMethod != SSHAuthPassword || !cfg.UsePassword || cfg.Password != "pw" || cfg.SSHKey != "" {
+		t.Errorf("unexpected password config: %+v", cfg)
+	}
+
+	d = schema.TestResourceDataRaw(t, sshTestSchema(), map[string]interface{}{
+		"name":     "bastion",
+		"username": "deploy",
+		"host":     "bastion.internal",
+		"port":     "22",
+		"password": "leftover",
+		"key":      key,
+	})
+	cfg, err = SchemaToSSHIntegrationConfiguration(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AuthMethod != SSHAuthPrivateKey || cfg.UsePassword || cfg.Password != "" || cfg.SSHKey != key {
+		t.Errorf("unexpected key config: %+v", cfg)
+	}
+}
+
+func TestValidateKnownHostKeys(t *testing.T) {
+	valid := "bastion.internal ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+	for _, v := range []string{
+		valid,
+		"# comment\n" + valid + "\n@cert-authority *.internal " + strings.Fields(valid)[1] + " " + strings.Fields(valid)[2] + "\n",
+	} {
+		if _, errs := ValidateKnownHostKeys(v, "known_host_keys.0"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range []string{"", "# only a comment", "bastion.internal ssh-ed25519 not-base64!", "ssh-ed25519"} {
+		if _, errs := ValidateKnownHostKeys(v, "known_host_keys.0"); len(errs) == 0 {
+			t.Errorf("expected %q to be rejected", v)
+		}
+	}
+}
+
+func TestSchemaToSSHIntegrationConfiguration_JumpHosts(t *testing.T) {
+	key, _, _ := testSSHKey(t, "s3cret")
+	s := sshTestSchema()
+	s["known_host_keys"] = &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}}
+	s["jump_host"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"host":            {Type: schema.TypeString, Required: true},
+				"port":            {Type: schema.TypeInt, Optional: true, Default: 22},
+				"username":        {Type: schema.TypeString, Required: true},
+				"key":             {Type: schema.TypeString, Required: true},
+				"known_host_keys": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{
+		"name":            "app",
+		"username":        "deploy",
+		"host":            "app.internal",
+		"port":            "22",
+		"key":             key,
+		"known_host_keys": []interface{}{"app.internal ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"},
+		"jump_host": []interface{}{
+			map[string]interface{}{"host": "edge.example.com", "username": "jump", "key": key},
+			map[string]interface{}{"host": "inner.internal", "port": 2222, "username": "jump", "key": key},
+		},
+	})
+
+	cfg, err := SchemaToSSHIntegrationConfiguration(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(cfg.KnownHostKeys, "app.internal ssh-ed25519 ") {
+		t.Errorf("known host keys not rendered: %q", cfg.KnownHostKeys)
+	}
+	if len(cfg.JumpHosts) != 2 || cfg.JumpHosts[0].Host != "edge.example.com" || cfg.JumpHosts[0].Port != 22 || cfg.JumpHosts[1].Port != 2222 {
+		t.Errorf("jump host chain not rendered in order: %+v", cfg.JumpHosts)
+	}
+}
@@ -12,7 +12,7 @@ type SyslogIntegrationConfiguration struct {
 func SchemaToSyslogIntegrationConfiguration(d *schema.ResourceData) SyslogIntegrationConfiguration {
 	return SyslogIntegrationConfiguration{
 		Name:     d.Get("name").(string),
-		Hostname: d.Get("hostname").(string),
+		Hostname: CanonicalString(d, "host"),
 		Port:     d.Get("port").(string),
 		Protocol: d.Get("protocol").(string),
 	}
@@ -0,0 +1,144 @@
+package integrations
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TLSMaterialAttributes are the adaptive_resource attributes holding PEM
+// encoded certificates. Each gets computed `<attr>_fingerprint` and
+// `<attr>_not_after` attributes.
+var TLSMaterialAttributes = []string{"root_cert", "tls_root_cert", "tls_cert_file", "cluster_cert", "clientcert"}
+
+// tlsChainAttributes hold a leaf certificate optionally followed by its
+// intermediates, which must be in order.
+var tlsChainAttributes = map[string]bool{"tls_cert_file": true}
+
+// tlsCABundleAttributes hold CA certificates that are independent of each
+// other. A bundle stays usable while any of its certificates is valid.
+var tlsCABundleAttributes = map[string]bool{"root_cert": true, "tls_root_cert": true, "cluster_cert": true}
+
+// tlsKeyOrCertAttributes may hold either a certificate or a private key.
+// Snowflake key-pair authentication uses `clientcert` for the private key.
+var tlsKeyOrCertAttributes = map[string]bool{"clientcert": true}
+
+// TLSMaterial summarises a validated PEM attribute.
+type TLSMaterial struct {
+	// Fingerprint is the SHA-256 fingerprint of the first certificate, as
+	// colon separated upper case hex. Empty for private keys.
+	Fingerprint string
+	// NotAfter is the earliest expiry of the unexpired certificates in the
+	// PEM.
+	NotAfter time.Time
+	// Expired describes the expired certificates of a CA bundle that still
+	// holds a valid one.
+	Expired []string
+}
+
+// NotAfterString formats NotAfter for the `*_not_after` attributes.
+func (m TLSMaterial) NotAfterString() string {
+	if m.NotAfter.IsZero() {
+		return ""
+	}
+	return m.NotAfter.UTC().Format(time.RFC3339)
+}
+
+// ExpiresWithin reports whether a certificate expires before now+window.
+func (m TLSMaterial) ExpiresWithin(now time.Time, window time.Duration) bool {
+	return !m.NotAfter.IsZero() && m.NotAfter.Before(now.Add(window))
+}
+
+// parseCertificatesPEM parses one or more PEM encoded certificates.
+func parseCertificatesPEM(s string) ([]*x509.Certificate, error) {
+	rest := []byte(strings.TrimSpace(s))
+	var certs []*x509.Certificate
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("expected a CERTIFICATE PEM block, got %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+func isPrivateKeyPEM(s string) bool {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(s)))
+	return block != nil && strings.HasSuffix(block.Type, "PRIVATE KEY")
+}
+
+func certificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// InspectTLSMaterial parses the PEM held by attr and checks it: certificates
+// must parse and not be expired, and chains must be ordered leaf first with
+// each certificate issued by the next. A CA bundle only fails when all of its
+// certificates have expired; the expired ones are listed in Expired.
+func InspectTLSMaterial(attr, value string, now time.Time) (TLSMaterial, error) {
+	var out TLSMaterial
+	if tlsKeyOrCertAttributes[attr] && isPrivateKeyPEM(value) {
+		return out, nil
+	}
+
+	certs, err := parseCertificatesPEM(value)
+	if err != nil {
+		return out, fmt.Errorf("`%s` is invalid: %w", attr, err)
+	}
+
+	out.Fingerprint = certificateFingerprint(certs[0])
+	var expired []string
+	for _, cert := range certs {
+		if now.After(cert.NotAfter) {
+			expired = append(expired, fmt.Sprintf("certificate %q expired on %s", cert.Subject.String(), cert.NotAfter.UTC().Format(time.RFC3339)))
+			continue
+		}
+		if out.NotAfter.IsZero() || cert.NotAfter.Before(out.NotAfter) {
+			out.NotAfter = cert.NotAfter
+		}
+	}
+	if len(expired) > 0 && (len(expired) == len(certs) || !tlsCABundleAttributes[attr]) {
+		return out, fmt.Errorf("`%s`: %s", attr, strings.Join(expired, "; "))
+	}
+	out.Expired = expired
+
+	if tlsChainAttributes[attr] {
+		for i := 0; i+1 < len(certs); i++ {
+			if err := certs[i].CheckSignatureFrom(certs[i+1]); err != nil {
+				return out, fmt.Errorf("`%s`: certificate %d (%q) is not issued by certificate %d (%q); order the chain leaf first, then each issuer", attr, i, certs[i].Subject.String(), i+1, certs[i+1].Subject.String())
+			}
+		}
+	}
+	return out, nil
+}
+
+// CheckTLSKeyPair verifies that key is the private key of the first
+// certificate in cert.
+func CheckTLSKeyPair(certAttr, keyAttr, cert, key string) error {
+	if _, err := tls.X509KeyPair([]byte(cert), []byte(key)); err != nil {
+		return fmt.Errorf("`%s` does not match `%s`: %w", keyAttr, certAttr, err)
+	}
+	return nil
+}
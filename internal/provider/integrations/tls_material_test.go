@@ -0,0 +1,114 @@
+package integrations
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testIssuer struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issueTestCertificate returns a certificate signed by parent, or
+// self-signed when parent is nil, valid until notAfter.
+func issueTestCertificate(t *testing.T, cn string, parent *testIssuer, notAfter time.Time) (*testIssuer, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testIssuer{cert: cert, key: key},
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestInspectTLSMaterial(t *testing.T) {
+	now := time.Now()
+	inYear := now.Add(365 * 24 * time.Hour)
+
+	root, rootPEM, _ := issueTestCertificate(t, "root", nil, inYear)
+	inter, interPEM, _ := issueTestCertificate(t, "intermediate", root, inYear)
+	_, leafPEM, leafKey := issueTestCertificate(t, "leaf", inter, now.Add(10*24*time.Hour))
+	_, expiredPEM, _ := issueTestCertificate(t, "expired", nil, now.Add(-time.Hour))
+	_, otherPEM, otherKey := issueTestCertificate(t, "other", nil, inYear)
+
+	m, err := InspectTLSMaterial("tls_cert_file", leafPEM+interPEM, now)
+	if err != nil {
+		t.Fatalf("ordered chain: %v", err)
+	}
+	if len(m.Fingerprint) != 95 || strings.ToUpper(m.Fingerprint) != m.Fingerprint {
+		t.Errorf("fingerprint = %q, want colon separated SHA-256", m.Fingerprint)
+	}
+	if !m.ExpiresWithin(now, 30*24*time.Hour) || m.ExpiresWithin(now, 5*24*time.Hour) {
+		t.Errorf("not_after = %s, want the leaf's expiry in 10 days", m.NotAfterString())
+	}
+
+	if _, err := InspectTLSMaterial("tls_cert_file", interPEM+leafPEM, now); err == nil || !strings.Contains(err.Error(), "order the chain") {
+		t.Errorf("misordered chain: got %v", err)
+	}
+	if _, err := InspectTLSMaterial("tls_root_cert", otherPEM+rootPEM, now); err != nil {
+		t.Errorf("CA bundles need not form a chain: %v", err)
+	}
+	if _, err := InspectTLSMaterial("root_cert", expiredPEM, now); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expired certificate: got %v", err)
+	}
+	if m, err := InspectTLSMaterial("root_cert", expiredPEM+rootPEM, now); err != nil || len(m.Expired) != 1 || !m.NotAfter.Equal(root.cert.NotAfter) {
+		t.Errorf("bundle with a valid certificate: got %+v, %v", m, err)
+	}
+	if _, err := InspectTLSMaterial("root_cert", expiredPEM+expiredPEM, now); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("bundle without a valid certificate: got %v", err)
+	}
+	if _, err := InspectTLSMaterial("tls_cert_file", expiredPEM+rootPEM, now); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("chain with an expired certificate: got %v", err)
+	}
+	if _, err := InspectTLSMaterial("root_cert", "not a certificate", now); err == nil {
+		t.Error("expected an error for garbage input")
+	}
+	if _, err := InspectTLSMaterial("root_cert", leafKey, now); err == nil {
+		t.Error("expected an error for a private key in root_cert")
+	}
+	if m, err := InspectTLSMaterial("clientcert", leafKey, now); err != nil || m.Fingerprint != "" {
+		t.Errorf("clientcert may hold a private key: %+v, %v", m, err)
+	}
+
+	if err := CheckTLSKeyPair("tls_cert_file", "tls_key_file", leafPEM+interPEM, leafKey); err != nil {
+		t.Errorf("matching key: %v", err)
+	}
+	if err := CheckTLSKeyPair("tls_cert_file", "tls_key_file", leafPEM, otherKey); err == nil {
+		t.Error("expected an error for a key of another certificate")
+	}
+}
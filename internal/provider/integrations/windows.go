@@ -15,8 +15,8 @@ func SchemaToRDPWindowsIntegrationConfiguration(d *schema.ResourceData) RDPWindo
 	return RDPWindowsIntegrationConfiguration{
 		Version:  "1.0",
 		Name:     d.Get("name").(string),
-		Hostname: d.Get("hostname").(string),
-		Password: d.Get("password").(string),
+		Hostname: CanonicalString(d, "host"),
+		Password: CanonicalString(d, "password"),
 		Username: d.Get("username").(string),
 		Port:     d.Get("port").(string),
 	}
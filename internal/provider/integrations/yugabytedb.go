@@ -12,10 +12,10 @@ type YugabyteDBIntegrationConfiguration struct {
 	Port     string `yaml:"port"`
 }
 
-func SchemaToYugabyteDBIntegrationConfiguration(d *schema.ResourceData) YugabyteDBIntegrationConfiguration {
-	sslMode := ""
-	if v, ok := d.GetOk("ssl_mode"); ok {
-		sslMode = v.(string)
+func SchemaToYugabyteDBIntegrationConfiguration(d *schema.ResourceData) (YugabyteDBIntegrationConfiguration, error) {
+	conn, err := SQLConnectionFromSchema(d, "yugabytedb")
+	if err != nil {
+		return YugabyteDBIntegrationConfiguration{}, err
 	}
 
 	rootCert := ""
@@ -25,11 +25,11 @@ func SchemaToYugabyteDBIntegrationConfiguration(d *schema.ResourceData) Yugabyte
 
 	return YugabyteDBIntegrationConfiguration{
 		Name:     d.Get("name").(string),
-		Hostname: d.Get("host").(string),
-		Username: d.Get("username").(string),
-		Password: d.Get("password").(string),
-		SSLMode:  sslMode,
+		Hostname: conn.Host,
+		Username: conn.Username,
+		Password: conn.Password,
+		SSLMode:  conn.SSLMode,
 		RootCert: rootCert,
-		Port:     d.Get("port").(string),
-	}
+		Port:     conn.Port,
+	}, nil
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func init() {
@@ -52,6 +53,47 @@ func New(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("ADAPTIVE_URL", "https://app.adaptive.live"),
 					Description: "The workspace to use for the provider. If not set, the default workspace will be used app.adaptive.live",
 				},
+				"protect_tags": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+					Description: "Resources and endpoints carrying any of these tags cannot be destroyed, as if `deletion_protection` were set on them. Eg. [\"prod\"]",
+				},
+				"adopt_existing": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Default for `adopt_existing` on resources, groups and scripts. When true, a create that fails because the name is taken adopts the existing object instead. Adoption looks the object up by name with GET /terraform/<type>/find/<name>, which current Adaptive backends do not serve; against them adoption fails with an error.",
+				},
+				"certificate_expiry_warning_days": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      30,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Warn when a certificate in a resource expires within this many days. The warning shows on refresh, so at the start of every plan once the resource exists, and after the apply that sets the certificate. Each provider alias uses its own value. Expired certificates are an error, unless other certificates in the same CA bundle are valid. Set to 0 to disable the warning.",
+				},
+				"endpoint_max_cpu": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      components.DefaultEndpointMaxCPU,
+					ValidateFunc: components.ValidateQuantity,
+					Description:  "Largest CPU quantity an `adaptive_endpoint` may request or be limited to, checked at plan time. This is a local setting: the provider does not read the workspace's limits, so keep it in sync with them by hand.",
+				},
+				"endpoint_max_memory": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      components.DefaultEndpointMaxMemory,
+					ValidateFunc: components.ValidateQuantity,
+					Description:  "Largest memory quantity an `adaptive_endpoint` may request or be limited to, checked at plan time. This is a local setting: the provider does not read the workspace's limits, so keep it in sync with them by hand.",
+				},
+				"endpoint_max_ephemeral_storage": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: components.ValidateQuantity,
+					Description:  "Largest ephemeral storage quantity an `adaptive_endpoint` may request or be limited to, checked at plan time. This is a local setting: the provider does not read the workspace's limits, so keep it in sync with them by hand. Unset means no maximum.",
+				},
 			},
 
 			ResourcesMap: map[string]*schema.Resource{
@@ -59,9 +101,17 @@ func New(version string) func() *schema.Provider {
 				"adaptive_resource":         components.ResourceAdaptiveResource(),
 				"adaptive_authorization":    components.ResourceAdaptiveAuthorization(),
 				"adaptive_group":            components.ResourceAdaptiveTeam(),
+				"adaptive_group_member":     components.ResourceAdaptiveTeamMember(),
+				"adaptive_group_endpoint":   components.ResourceAdaptiveTeamEndpoint(),
 				"adaptive_script":           components.ResourceAdaptiveScript(),
 				"adaptive_schedule":         components.ResourceAdaptiveSchedule(),
+				"adaptive_approval_policy":  components.ResourceAdaptiveApprovalPolicy(),
+				"adaptive_access_request":   components.ResourceAdaptiveAccessRequest(),
 				"adaptive_msteams_workflow": integrations.ResourceAdaptiveMSTeamsWorkflow(),
+				"adaptive_rdp_target":       integrations.ResourceAdaptiveRDPTarget(),
+			},
+			DataSourcesMap: map[string]*schema.Resource{
+				"adaptive_schedule_windows": components.DataSourceAdaptiveScheduleWindows(),
 			}, ConfigureContextFunc: providerConfigure,
 		}
 		return p
@@ -135,9 +185,19 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 
 	svcToken, wsURL, err := tryReadingServiceToken(serviceToken, workspaceURL)
 	if err != nil {
-		return nil, diag.Errorf(fmt.Sprintf("bad service token: %s", err))
+		return nil, diag.Errorf("bad service token: %s", err)
 	}
 	c := client.NewClient(svcToken, wsURL)
+	c.Options.AdoptExisting = d.Get("adopt_existing").(bool)
+	c.Options.CertificateExpiryWarningDays = d.Get("certificate_expiry_warning_days").(int)
+	c.Options.EndpointMaxCPU = d.Get("endpoint_max_cpu").(string)
+	c.Options.EndpointMaxMemory = d.Get("endpoint_max_memory").(string)
+	c.Options.EndpointMaxEphemeralStorage = d.Get("endpoint_max_ephemeral_storage").(string)
+	for _, tag := range d.Get("protect_tags").([]interface{}) {
+		if t, ok := tag.(string); ok && t != "" {
+			c.Options.ProtectTags = append(c.Options.ProtectTags, t)
+		}
+	}
 
 	return c, nil
 }
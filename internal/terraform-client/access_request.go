@@ -0,0 +1,169 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Access request statuses. Pending is the only non-final status.
+const (
+	AccessRequestPending  = "pending"
+	AccessRequestApproved = "approved"
+	AccessRequestDenied   = "denied"
+	AccessRequestExpired  = "expired"
+	AccessRequestRevoked  = "revoked"
+)
+
+// AccessRequest asks for JIT access to an endpoint. It is approved by the
+// endpoint's JIT approvers or approval policy. An empty Requester means the
+// owner of the service token.
+type AccessRequest struct {
+	Endpoint        string `json:"endpoint"`
+	Requester       string `json:"requester,omitempty"`
+	Duration        string `json:"duration"`
+	Justification   string `json:"justification,omitempty"`
+	TicketReference string `json:"ticketReference,omitempty"`
+}
+
+// AccessRequestResponse is an access request and its progress. ExpiresAt is
+// when the grant ends, or when a pending request expires unapproved.
+type AccessRequestResponse struct {
+	ID string `json:"id"`
+	AccessRequest
+	Status    string `json:"status"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// ErrAccessRequestGone is returned by WaitForAccessRequest when the request
+// is deleted while it is being waited for.
+var ErrAccessRequestGone = errors.New("access request no longer exists")
+
+func (c *Client) accessRequestAPI() string {
+	return fmt.Sprintf("%s/terraform/access-request", c.workspaceURL)
+}
+
+func (c *Client) CreateAccessRequest(ctx context.Context, req *AccessRequest) (*AccessRequestResponse, error) {
+	tflog.Debug(ctx, "CreateAccessRequest called", map[string]interface{}{"endpoint": req.Endpoint, "requester": req.Requester})
+	payloadBuf := bytes.NewBuffer([]byte{})
+	if err := json.NewEncoder(payloadBuf).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to json encode request body. err %w", err)
+	}
+
+	request, err := http.NewRequest("POST", fmt.Sprintf("%s/create", c.accessRequestAPI()), payloadBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		if msg, derr := decodeError(ctx, response); derr == nil && msg != "" {
+			return nil, fmt.Errorf("access request for endpoint %q: %s", req.Endpoint, msg)
+		}
+		return nil, fmt.Errorf("error requesting access to endpoint %q (status %d)", req.Endpoint, response.StatusCode)
+	}
+
+	var resp AccessRequestResponse
+	if err := json.NewDecoder(response.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response body. err %w", err)
+	}
+	return &resp, nil
+}
+
+// GetAccessRequest reads an access request. It returns (nil, nil) when the
+// request no longer exists so callers can drop it from Terraform state.
+func (c *Client) GetAccessRequest(ctx context.Context, id string) (*AccessRequestResponse, error) {
+	tflog.Debug(ctx, "GetAccessRequest called", map[string]interface{}{"id": id})
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/read/%s", c.accessRequestAPI(), id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading access request %s (status %d)", id, response.StatusCode)
+	}
+
+	var resp AccessRequestResponse
+	if err := json.NewDecoder(response.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response body. err %w", err)
+	}
+	return &resp, nil
+}
+
+// WaitForAccessRequest polls an access request every interval until it
+// leaves the pending status, and returns it then. It returns ErrTimeout if
+// the request is still pending after timeout. interval must be positive.
+func (c *Client) WaitForAccessRequest(ctx context.Context, id string, timeout, interval time.Duration) (*AccessRequestResponse, error) {
+	tflog.Debug(ctx, "WaitForAccessRequest called", map[string]interface{}{"id": id, "timeout": timeout.String()})
+	resp, err := Do(
+		func() (*AccessRequestResponse, error) {
+			resp, err := c.GetAccessRequest(ctx, id)
+			if err == nil && resp == nil {
+				return nil, fmt.Errorf("%w: %s", ErrAccessRequestGone, id)
+			}
+			return resp, err
+		},
+		Timeout(timeout),
+		// Bounded by the timeout rather than a number of attempts.
+		RetryLimit(int(timeout/interval)+1),
+		Sleep(interval),
+		RetryChecker(func(result any, err error) bool {
+			// Transient read errors are retried; a deleted request is not.
+			return err != nil && !errors.Is(err, ErrAccessRequestGone) && ctx.Err() == nil
+		}),
+		RetryResultChecker(func(result any) bool {
+			// Errors are left to RetryChecker.
+			resp, ok := result.(*AccessRequestResponse)
+			return ok && resp != nil && resp.Status == AccessRequestPending
+		}),
+	)
+	if errors.Is(err, ErrMaxRetriesReached) {
+		return nil, fmt.Errorf("%w: %v", ErrTimeout, err)
+	}
+	return resp, err
+}
+
+// RevokeAccessRequest ends an access grant, or withdraws a pending request.
+// Revoking a request that is no longer active, or no longer exists, succeeds.
+func (c *Client) RevokeAccessRequest(ctx context.Context, id string) error {
+	tflog.Debug(ctx, "RevokeAccessRequest called", map[string]interface{}{"id": id})
+	request, err := http.NewRequest("POST", fmt.Sprintf("%s/revoke/%s", c.accessRequestAPI(), id), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		if msg, derr := decodeError(ctx, response); derr == nil && msg != "" {
+			return errors.New(msg)
+		}
+		return fmt.Errorf("error revoking access request %s (status %d)", id, response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ApprovalPolicy describes how JIT access requests are approved. Stages are
+// approved in order; a request is granted once every stage has MinApprovals
+// approvals from its approvers.
+type ApprovalPolicy struct {
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Stages      []ApprovalStage `json:"stages"`
+
+	AllowSelfApproval      bool `json:"allowSelfApproval"`
+	RequireJustification   bool `json:"requireJustification"`
+	JustificationMinLength int  `json:"justificationMinLength,omitempty"`
+
+	// MaxGrantDuration caps the access an approval grants, and
+	// PendingRequestExpiry how long a request waits for approval before it
+	// expires. Both use the endpoint duration notation, such as "4h" or "7d".
+	MaxGrantDuration     string `json:"maxGrantDuration,omitempty"`
+	PendingRequestExpiry string `json:"pendingRequestExpiry,omitempty"`
+}
+
+// ApprovalStage is one level of an approval policy. Approvers are user
+// emails and group names.
+type ApprovalStage struct {
+	ApproverUsers  []string `json:"approverUsers,omitempty"`
+	ApproverGroups []string `json:"approverGroups,omitempty"`
+	MinApprovals   int      `json:"minApprovals"`
+}
+
+// ApprovalPolicyResponse is an approval policy as stored by the backend.
+type ApprovalPolicyResponse struct {
+	ID string `json:"id"`
+	ApprovalPolicy
+}
+
+// EndpointApproval attaches an approval policy to an endpoint, either a shared
+// adaptive_approval_policy by name or one defined inline. At most one is set.
+// Both are always sent, so an empty name and a null policy detach the policy
+// when it is removed from the configuration.
+type EndpointApproval struct {
+	ApprovalPolicyName string          `json:"approvalPolicyName"`
+	ApprovalPolicy     *ApprovalPolicy `json:"approvalPolicy"`
+}
+
+func (c *Client) approvalPolicyAPI() string {
+	return fmt.Sprintf("%s/terraform/approval-policy", c.workspaceURL)
+}
+
+func (c *Client) writeApprovalPolicy(ctx context.Context, url string, req *ApprovalPolicy) (*ApprovalPolicyResponse, error) {
+	payloadBuf := bytes.NewBuffer([]byte{})
+	if err := json.NewEncoder(payloadBuf).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to json encode request body. err %w", err)
+	}
+
+	request, err := http.NewRequest("POST", url, payloadBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		if msg, derr := decodeError(ctx, response); derr == nil && msg != "" {
+			return nil, fmt.Errorf("approval policy %q: %s", req.Name, msg)
+		}
+		return nil, fmt.Errorf("error writing approval policy %q (status %d)", req.Name, response.StatusCode)
+	}
+
+	var resp ApprovalPolicyResponse
+	if err := json.NewDecoder(response.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response body. err %w", err)
+	}
+	return &resp, nil
+}
+
+func (c *Client) CreateApprovalPolicy(ctx context.Context, req *ApprovalPolicy) (*ApprovalPolicyResponse, error) {
+	tflog.Debug(ctx, "CreateApprovalPolicy called", map[string]interface{}{"name": req.Name})
+	return c.writeApprovalPolicy(ctx, fmt.Sprintf("%s/create", c.approvalPolicyAPI()), req)
+}
+
+func (c *Client) UpdateApprovalPolicy(ctx context.Context, id string, req *ApprovalPolicy) (*ApprovalPolicyResponse, error) {
+	tflog.Debug(ctx, "UpdateApprovalPolicy called", map[string]interface{}{"id": id, "name": req.Name})
+	return c.writeApprovalPolicy(ctx, fmt.Sprintf("%s/update/%s", c.approvalPolicyAPI(), id), req)
+}
+
+// GetApprovalPolicy reads an approval policy. It returns (nil, nil) when the
+// policy no longer exists so callers can drop it from Terraform state.
+func (c *Client) GetApprovalPolicy(ctx context.Context, id string) (*ApprovalPolicyResponse, error) {
+	tflog.Debug(ctx, "GetApprovalPolicy called", map[string]interface{}{"id": id})
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/read/%s", c.approvalPolicyAPI(), id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading approval policy %s (status %d)", id, response.StatusCode)
+	}
+
+	var resp ApprovalPolicyResponse
+	if err := json.NewDecoder(response.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response body. err %w", err)
+	}
+	return &resp, nil
+}
+
+// DeleteApprovalPolicy removes an approval policy. The backend refuses to
+// delete a policy that endpoints still reference.
+func (c *Client) DeleteApprovalPolicy(ctx context.Context, id, name string) error {
+	tflog.Debug(ctx, "DeleteApprovalPolicy called", map[string]interface{}{"id": id, "name": name})
+	request, err := http.NewRequest("POST", fmt.Sprintf("%s/delete/%s", c.approvalPolicyAPI(), id), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		if msg, derr := decodeError(ctx, response); derr == nil && msg != "" {
+			return errors.New(msg)
+		}
+		return fmt.Errorf("error deleting approval policy %q (status %d)", name, response.StatusCode)
+	}
+	return nil
+}
+
+// GetEndpointApproval reads the approval policy attached to an endpoint. It
+// returns (nil, nil) when the endpoint no longer exists or the backend does
+// not report approval policies, so callers can leave state as it is.
+func (c *Client) GetEndpointApproval(ctx context.Context, sessionID string) (*EndpointApproval, error) {
+	tflog.Debug(ctx, "GetEndpointApproval called", map[string]interface{}{"session_id": sessionID})
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/read/%s", c.sessionAPI(), sessionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("error reading endpoint %s (status %d)", sessionID, response.StatusCode)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(response.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode response body. err %w", err)
+	}
+	name, hasName := raw["approvalPolicyName"]
+	policy, hasPolicy := raw["approvalPolicy"]
+	if !hasName && !hasPolicy {
+		return nil, nil
+	}
+	approval := &EndpointApproval{}
+	if hasName {
+		if err := json.Unmarshal(name, &approval.ApprovalPolicyName); err != nil {
+			return nil, fmt.Errorf("failed to decode approvalPolicyName. err %w", err)
+		}
+	}
+	if hasPolicy {
+		if err := json.Unmarshal(policy, &approval.ApprovalPolicy); err != nil {
+			return nil, fmt.Errorf("failed to decode approvalPolicy. err %w", err)
+		}
+	}
+	return approval, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -20,6 +21,45 @@ type Client struct {
 	serviceToken string
 	workspaceURL string
 	httpClient   *http.Client
+
+	// Options holds provider-level settings that resources consult. They
+	// are never sent to the Adaptive API.
+	Options ProviderOptions
+}
+
+// ProviderOptions are provider-wide defaults configured in the provider block.
+type ProviderOptions struct {
+	// ProtectTags refuses to delete any resource or endpoint carrying one
+	// of these tags.
+	ProtectTags []string
+	// AdoptExisting is the default for a resource's adopt_existing.
+	AdoptExisting bool
+	// CertificateExpiryWarningDays warns about certificates in resources
+	// that expire within this many days. Zero disables the warning.
+	CertificateExpiryWarningDays int
+	// EndpointMaxCPU, EndpointMaxMemory and EndpointMaxEphemeralStorage are
+	// Kubernetes quantities capping endpoint pod resources at plan time.
+	// Empty means no maximum.
+	EndpointMaxCPU              string
+	EndpointMaxMemory           string
+	EndpointMaxEphemeralStorage string
+}
+
+// DuplicateNameError is returned when the API rejects a write with 409
+// because another object already uses the name.
+type DuplicateNameError struct {
+	Kind string
+	Name string
+}
+
+func (e *DuplicateNameError) Error() string {
+	return fmt.Sprintf("duplicate %s with name %s", e.Kind, e.Name)
+}
+
+// IsDuplicateName returns true if the cause of the given error is a DuplicateNameError.
+func IsDuplicateName(err error) bool {
+	var dup *DuplicateNameError
+	return errors.As(err, &dup)
 }
 
 func NewClient(serviceToken, workspaceURL string) *Client {
@@ -57,6 +97,70 @@ func (c *Client) sessionAPI() string {
 	return fmt.Sprintf("%s/terraform/session", c.workspaceURL)
 }
 
+// findByName looks an object up by name. It returns (nil, nil) when no object
+// of that kind has the name. The API has no list endpoint to search, and the
+// read endpoints take an ID, so this needs GET {api}/find/{name} on the
+// backend; only adopt_existing uses it.
+func (c *Client) findByName(ctx context.Context, api, kind, name string) (*NamedObject, error) {
+	tflog.Debug(ctx, "Looking up object by name", map[string]interface{}{
+		"kind": kind,
+		"name": name,
+	})
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/find/%s", api, url.PathEscape(name)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		tflog.Error(ctx, "Failed to make request to adaptive API for name lookup", map[string]interface{}{
+			"kind":  kind,
+			"name":  name,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		tflog.Error(ctx, "Failed to look up object by name", map[string]interface{}{
+			"kind":        kind,
+			"name":        name,
+			"status_code": response.StatusCode,
+		})
+		return nil, fmt.Errorf("error looking up %s %s (status %d)", kind, name, response.StatusCode)
+	}
+
+	var resp NamedObject
+	if err := json.NewDecoder(response.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response body. err %w", err)
+	}
+	return &resp, nil
+}
+
+// FindResourceByName returns the resource with the given name, or nil.
+func (c *Client) FindResourceByName(ctx context.Context, name string) (*NamedObject, error) {
+	return c.findByName(ctx, c.resourceAPI(), "resource", name)
+}
+
+// FindTeamByName returns the group with the given name, or nil.
+func (c *Client) FindTeamByName(ctx context.Context, name string) (*NamedObject, error) {
+	return c.findByName(ctx, c.teamAPI(), "group", name)
+}
+
+// FindAuthorizationByName returns the authorization with the given name, or nil.
+func (c *Client) FindAuthorizationByName(ctx context.Context, name string) (*NamedObject, error) {
+	return c.findByName(ctx, c.authorizationAPI(), "authorization", name)
+}
+
+// FindScriptByName returns the script with the given name, or nil.
+func (c *Client) FindScriptByName(ctx context.Context, name string) (*NamedObject, error) {
+	return c.findByName(ctx, c.scriptAPI(), "script", name)
+}
+
 func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
 	tflog.Debug(ctx, "Making HTTP request", map[string]interface{}{
 		"method": req.Method,
@@ -228,7 +332,7 @@ func (c *Client) UpdateScript(ctx context.Context, id, name, command, endpoint *
 			"script_id": *id,
 			"name":      *name,
 		})
-		return nil, fmt.Errorf("duplicate script with name %s", *name)
+		return nil, &DuplicateNameError{Kind: "script", Name: *name}
 	}
 	if response.StatusCode != 200 {
 		tflog.Error(ctx, "Failed to update script", map[string]interface{}{
@@ -330,7 +434,7 @@ func (c *Client) CreateTeam(ctx context.Context, name *string, members, endpoint
 		tflog.Error(ctx, "Duplicate group/team detected", map[string]interface{}{
 			"name": *name,
 		})
-		return nil, fmt.Errorf("duplicate group with name %s", *name)
+		return nil, &DuplicateNameError{Kind: "group", Name: *name}
 	}
 	if response.StatusCode != 200 {
 		decodedMsg, err := decodeError(ctx, response)
@@ -410,12 +514,19 @@ func (c *Client) UpdateTeam(ctx context.Context, id, name *string, members, endp
 		"team_id": *id,
 		"name":    *name,
 	})
+	// nil lists are left out so the backend keeps the group's current
+	// members or endpoints, which may be managed by other resources.
+	body := map[string]interface{}{
+		"Name": name,
+	}
+	if members != nil {
+		body["Members"] = members
+	}
+	if endpoints != nil {
+		body["Endpoints"] = endpoints
+	}
 	payloadBuf := bytes.NewBuffer([]byte{})
-	if err := json.NewEncoder(payloadBuf).Encode(map[string]interface{}{
-		"Name":      name,
-		"Members":   members,
-		"Endpoints": endpoints,
-	}); err != nil {
+	if err := json.NewEncoder(payloadBuf).Encode(body); err != nil {
 		tflog.Error(ctx, "Failed to encode request body for updating team", map[string]interface{}{
 			"team_id": *id,
 			"error":   err.Error(),
@@ -32,6 +32,9 @@ func (c *Client) CreateSession(
 	groups []string,
 	idleTimeout string,
 	scriptOnlyAccess bool,
+	resources *EndpointResources,
+	typeOptions SessionTypeOptions,
+	approval EndpointApproval,
 ) (*CreateSessionResponse, error) {
 	tflog.Debug(ctx, "CreateSession called", map[string]interface{}{
 		"name":          sessionName,
@@ -41,22 +44,25 @@ func (c *Client) CreateSession(
 		"type":          sessionType,
 	})
 	req := CreateSessionRequest{
-		SessionName:       sessionName,
-		ResourceName:      resourceName,
-		ClusterName:       clusterName,
-		AuthorizationName: authorizationName,
-		SessionTTL:        ttl,
-		SessionType:       sessionType,
-		SessionUsers:      users,
-		IsJITEnabled:      isJITEnabled,
-		AccessApprovers:   accessApprovers,
-		PauseTimeout:      pauseTimeout,
-		IdleTimeout:       idleTimeout,
-		Memory:            memory,
-		CPU:               cpu,
-		UsersTags:         tags,
-		Groups:            groups,
-		ScriptOnlyAccess:  scriptOnlyAccess,
+		SessionName:        sessionName,
+		ResourceName:       resourceName,
+		ClusterName:        clusterName,
+		AuthorizationName:  authorizationName,
+		SessionTTL:         ttl,
+		SessionType:        sessionType,
+		SessionUsers:       users,
+		IsJITEnabled:       isJITEnabled,
+		AccessApprovers:    accessApprovers,
+		PauseTimeout:       pauseTimeout,
+		IdleTimeout:        idleTimeout,
+		Memory:             memory,
+		CPU:                cpu,
+		UsersTags:          tags,
+		Groups:             groups,
+		ScriptOnlyAccess:   scriptOnlyAccess,
+		Resources:          resources,
+		SessionTypeOptions: typeOptions,
+		EndpointApproval:   approval,
 	}
 
 	payloadBuf := bytes.NewBuffer([]byte{})
@@ -269,6 +275,9 @@ func (c *Client) UpdateSession(
 	groups []string,
 	idleTimeout string,
 	scriptOnlyAccess bool,
+	resources *EndpointResources,
+	typeOptions SessionTypeOptions,
+	approval EndpointApproval,
 ) (*UpdateSessionResponse, error) {
 	tflog.Debug(ctx, "UpdateSession called", map[string]interface{}{
 		"session_id": sessionID,
@@ -276,22 +285,25 @@ func (c *Client) UpdateSession(
 		"resource":   resourceName,
 	})
 	req := UpdateSessionRequest{
-		SessionName:       sessionName,
-		ResourceName:      resourceName,
-		ClusterName:       clusterName,
-		SessionType:       sessionType,
-		AuthorizationName: authorizationName,
-		SessionTTL:        ttl,
-		SessionUsers:      users,
-		IsJITEnabled:      isJITEnabled,
-		AccessApprovers:   accessApprovers,
-		PauseTimeout:      pauseTimeout,
-		Memory:            memory,
-		CPU:               cpu,
-		UsersTags:         tags,
-		Groups:            groups,
-		IdleTimeout:       idleTimeout,
-		ScriptOnlyAccess:  scriptOnlyAccess,
+		SessionName:        sessionName,
+		ResourceName:       resourceName,
+		ClusterName:        clusterName,
+		SessionType:        sessionType,
+		AuthorizationName:  authorizationName,
+		SessionTTL:         ttl,
+		SessionUsers:       users,
+		IsJITEnabled:       isJITEnabled,
+		AccessApprovers:    accessApprovers,
+		PauseTimeout:       pauseTimeout,
+		Memory:             memory,
+		CPU:                cpu,
+		UsersTags:          tags,
+		Groups:             groups,
+		IdleTimeout:        idleTimeout,
+		ScriptOnlyAccess:   scriptOnlyAccess,
+		Resources:          resources,
+		SessionTypeOptions: typeOptions,
+		EndpointApproval:   approval,
 	}
 
 	payloadBuf := bytes.NewBuffer([]byte{})
@@ -469,8 +481,6 @@ func (c *Client) DeleteSession(ctx context.Context, sessionID string) (bool, err
 		})
 		return false, errors.New("could not delete session")
 	}
-
-	return true, nil
 }
 
 func (c *Client) deleteSession(ctx context.Context, sessionID string) (bool, error) {
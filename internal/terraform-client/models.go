@@ -10,6 +10,7 @@ type CreateResourceRequest struct {
 
 type UpdateResourceRequest struct {
 	IntegrationType string   `json:"integrationType"`
+	Name            string   `json:"name,omitempty"`
 	Configuration   string   `json:"config"`
 	UserTags        []string `json:"userTags"`
 	DefaultCluster  string   `json:"defaultCluster,omitempty"`
@@ -52,6 +53,37 @@ type CreateSessionRequest struct {
 
 	// script only access
 	ScriptOnlyAccess bool `json:"script_only_access"`
+
+	// separate pod requests and limits; Memory and CPU carry a single
+	// value for backends that predate them
+	Resources *EndpointResources `json:"resources,omitempty"`
+
+	// ports of a services endpoint, scripts of a script endpoint
+	SessionTypeOptions
+
+	// multi-stage JIT approval; supersedes AccessApprovers
+	EndpointApproval
+}
+
+// SessionTypeOptions are the options of a services or script endpoint. Only
+// those of the endpoint's session type are set.
+type SessionTypeOptions struct {
+	ExposedPorts   []int    `json:"exposedPorts,omitempty"`
+	AllowedScripts []string `json:"allowedScripts,omitempty"`
+}
+
+// EndpointResources are the Kubernetes requests and limits of an endpoint pod.
+type EndpointResources struct {
+	Requests *EndpointResourceList `json:"requests,omitempty"`
+	Limits   *EndpointResourceList `json:"limits,omitempty"`
+}
+
+// EndpointResourceList holds Kubernetes quantity strings, such as "500m" or
+// "6Gi". Empty values are left to the backend's defaults.
+type EndpointResourceList struct {
+	CPU              string `json:"cpu,omitempty"`
+	Memory           string `json:"memory,omitempty"`
+	EphemeralStorage string `json:"ephemeralStorage,omitempty"`
 }
 
 type CreateSessionResponse struct {
@@ -86,6 +118,16 @@ type UpdateAuthorizationResponse struct {
 	ID string `json:"id"`
 }
 
+// NamedObject is the summary returned by the find-by-name endpoints. Only the
+// fields relevant to the object kind are populated.
+type NamedObject struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	IntegrationType string `json:"integrationType,omitempty"`
+	ResourceType    string `json:"resourceType,omitempty"`
+	Endpoint        string `json:"endpoint,omitempty"`
+}
+
 type ErrorResponse struct {
 	Error string
 	Msg   string `json:",omitempty"`
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RDPTarget is a single Windows host in an adaptive_rdp fleet. Field names
+// match the backend TargetConfig.
+type RDPTarget struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Record   *bool  `json:"record,omitempty"`
+}
+
+func (c *Client) rdpTargetAPI(fleetID string) string {
+	return fmt.Sprintf("%s/%s/targets", c.resourceAPI(), url.PathEscape(fleetID))
+}
+
+func (c *Client) writeRDPTarget(ctx context.Context, fleetID, url string, target *RDPTarget) error {
+	payloadBuf := bytes.NewBuffer([]byte{})
+	if err := json.NewEncoder(payloadBuf).Encode(target); err != nil {
+		return fmt.Errorf("failed to json encode request body. err %w", err)
+	}
+
+	request, err := http.NewRequest("POST", url, payloadBuf)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		tflog.Error(ctx, "Failed to make request to adaptive API for RDP target", map[string]interface{}{
+			"fleet_id":  fleetID,
+			"target_id": target.ID,
+			"error":     err.Error(),
+		})
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+	if response.StatusCode == http.StatusConflict {
+		return &DuplicateNameError{Kind: "rdp target", Name: target.ID}
+	}
+	if msg, derr := decodeError(ctx, response); derr == nil && msg != "" {
+		return fmt.Errorf("rdp target %q: %s", target.ID, msg)
+	}
+	return fmt.Errorf("error writing rdp target %q in fleet %s (status %d)", target.ID, fleetID, response.StatusCode)
+}
+
+// CreateRDPTarget adds a target to an adaptive_rdp fleet. A target whose ID is
+// already used in the fleet is rejected with a DuplicateNameError.
+func (c *Client) CreateRDPTarget(ctx context.Context, fleetID string, target *RDPTarget) error {
+	tflog.Debug(ctx, "CreateRDPTarget called", map[string]interface{}{"fleet_id": fleetID, "target_id": target.ID})
+	return c.writeRDPTarget(ctx, fleetID, fmt.Sprintf("%s/create", c.rdpTargetAPI(fleetID)), target)
+}
+
+// UpdateRDPTarget replaces a single target of a fleet, leaving the others alone.
+func (c *Client) UpdateRDPTarget(ctx context.Context, fleetID string, target *RDPTarget) error {
+	tflog.Debug(ctx, "UpdateRDPTarget called", map[string]interface{}{"fleet_id": fleetID, "target_id": target.ID})
+	return c.writeRDPTarget(ctx, fleetID, fmt.Sprintf("%s/update/%s", c.rdpTargetAPI(fleetID), url.PathEscape(target.ID)), target)
+}
+
+// GetRDPTarget reads a target of a fleet. The password is never returned. It
+// returns (nil, nil) when the fleet or the target no longer exists.
+func (c *Client) GetRDPTarget(ctx context.Context, fleetID, targetID string) (*RDPTarget, error) {
+	tflog.Debug(ctx, "GetRDPTarget called", map[string]interface{}{"fleet_id": fleetID, "target_id": targetID})
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/read/%s", c.rdpTargetAPI(fleetID), url.PathEscape(targetID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading rdp target %q in fleet %s (status %d)", targetID, fleetID, response.StatusCode)
+	}
+
+	var resp RDPTarget
+	if err := json.NewDecoder(response.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response body. err %w", err)
+	}
+	return &resp, nil
+}
+
+// DeleteRDPTarget removes a target from a fleet. A target that is already gone
+// is treated as deleted.
+func (c *Client) DeleteRDPTarget(ctx context.Context, fleetID, targetID string) error {
+	tflog.Debug(ctx, "DeleteRDPTarget called", map[string]interface{}{"fleet_id": fleetID, "target_id": targetID})
+	request, err := http.NewRequest("POST", fmt.Sprintf("%s/delete/%s", c.rdpTargetAPI(fleetID), url.PathEscape(targetID)), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK || response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if msg, derr := decodeError(ctx, response); derr == nil && msg != "" {
+		return errors.New(msg)
+	}
+	return fmt.Errorf("error deleting rdp target %q in fleet %s (status %d)", targetID, fleetID, response.StatusCode)
+}
@@ -130,7 +130,7 @@ func (c *Client) CreateResource(
 		tflog.Error(ctx, "Duplicate resource detected", map[string]interface{}{
 			"name": name,
 		})
-		return nil, fmt.Errorf("duplicate resource with name %s", name)
+		return nil, &DuplicateNameError{Kind: "resource", Name: name}
 	}
 	if response.StatusCode != 200 {
 		tflog.Error(ctx, "Failed to create resource", map[string]interface{}{
@@ -154,20 +154,24 @@ func (c *Client) CreateResource(
 	return &resp, nil
 }
 
+// UpdateResource updates a resource in place. A non-empty name renames it;
+// endpoints reference resources by name and are re-pointed by the backend.
 func (c *Client) UpdateResource(
 	ctx context.Context,
 	resourceID string,
-	rType string,
+	name, rType string,
 	yamlRConfig []byte,
 	tags []string,
 	defaultCluster string,
 ) (*UpdateResourceResponse, error) {
 	tflog.Debug(ctx, "UpdateResource called", map[string]interface{}{
 		"resource_id": resourceID,
+		"name":        name,
 		"type":        rType,
 	})
 	req := UpdateResourceRequest{
 		IntegrationType: rType,
+		Name:            name,
 		Configuration:   string(yamlRConfig),
 		UserTags:        tags,
 		DefaultCluster:  defaultCluster,
@@ -200,6 +204,13 @@ func (c *Client) UpdateResource(
 		})
 		return nil, err
 	}
+	if response.StatusCode == 409 {
+		tflog.Error(ctx, "Duplicate resource name during update", map[string]interface{}{
+			"resource_id": resourceID,
+			"name":        name,
+		})
+		return nil, &DuplicateNameError{Kind: "resource", Name: name}
+	}
 	if response.StatusCode != 200 {
 		tflog.Error(ctx, "Failed to update resource", map[string]interface{}{
 			"resource_id": resourceID,
@@ -310,7 +321,7 @@ func (c *Client) CreateScript(ctx context.Context, name, command, endpoint strin
 		tflog.Error(ctx, "Duplicate script detected", map[string]interface{}{
 			"name": name,
 		})
-		return nil, fmt.Errorf("duplicate script with name %s", name)
+		return nil, &DuplicateNameError{Kind: "script", Name: name}
 	}
 	if response.StatusCode != 200 {
 		tflog.Error(ctx, "Failed to create script", map[string]interface{}{
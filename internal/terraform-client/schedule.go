@@ -7,7 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -31,6 +33,16 @@ type ScheduleRequest struct {
 	EndDay        int      `json:"endDay,omitempty"`
 	SpecificDates []string `json:"specificDates,omitempty"`
 
+	// Windows, when set, replace the single StartHour:StartMinute to
+	// EndHour:EndMinute window.
+	Windows []ScheduleTimeWindow `json:"windows,omitempty"`
+
+	// RRule, ExcludeDates and HolidayCalendar have no backend field. They are
+	// evaluated locally and sent as the specific dates ExpandRecurrence lists.
+	RRule           string   `json:"-"`
+	ExcludeDates    []string `json:"-"`
+	HolidayCalendar string   `json:"-"`
+
 	Users     []string `json:"users,omitempty"`
 	Teams     []string `json:"teams,omitempty"`
 	Endpoints []string `json:"endpoints,omitempty"`
@@ -43,6 +55,10 @@ type ScheduleRequest struct {
 
 // ScheduleResponse mirrors handler.TerraformScheduleResponse. On a 400 the
 // backend reuses this shape to report names it could not resolve to IDs.
+//
+// The pattern and membership fields are pointers: they are nil when the
+// backend omits them, as backends predating the full read view do, so callers
+// can tell "not reported" from "empty".
 type ScheduleResponse struct {
 	ID              string   `json:"id"`
 	Name            string   `json:"name"`
@@ -54,11 +70,39 @@ type ScheduleResponse struct {
 	OperationType   string   `json:"operationType,omitempty"`
 	ExpiresAt       string   `json:"expiresAt,omitempty"`
 
+	Description   *string `json:"description,omitempty"`
+	StartHour     *int    `json:"startHour,omitempty"`
+	StartMinute   *int    `json:"startMinute,omitempty"`
+	EndHour       *int    `json:"endHour,omitempty"`
+	EndMinute     *int    `json:"endMinute,omitempty"`
+	StartDay      *int    `json:"startDay,omitempty"`
+	EndDay        *int    `json:"endDay,omitempty"`
+	MaxAccessTime *int    `json:"maxAccessTime,omitempty"`
+
+	Weekdays      *[]string             `json:"weekdays,omitempty"`
+	SpecificDates *[]string             `json:"specificDates,omitempty"`
+	Windows       *[]ScheduleTimeWindow `json:"windows,omitempty"`
+	Users         *[]string             `json:"users,omitempty"`
+	Teams         *[]string             `json:"teams,omitempty"`
+	Endpoints     *[]string             `json:"endpoints,omitempty"`
+
 	UnresolvedUsers     []string `json:"unresolvedUsers,omitempty"`
 	UnresolvedTeams     []string `json:"unresolvedTeams,omitempty"`
 	UnresolvedEndpoints []string `json:"unresolvedEndpoints,omitempty"`
 }
 
+// ScheduleTimeWindow is one time-of-day window of a schedule. An end at or
+// before the start runs past midnight into the next day; EndHour 24 is
+// midnight at the end of the day. Weekdays, when set, restrict the window to
+// days starting on those weekdays.
+type ScheduleTimeWindow struct {
+	StartHour   int      `json:"startHour"`
+	StartMinute int      `json:"startMinute"`
+	EndHour     int      `json:"endHour"`
+	EndMinute   int      `json:"endMinute"`
+	Weekdays    []string `json:"weekdays,omitempty"`
+}
+
 func (c *Client) scheduleAPI() string {
 	return fmt.Sprintf("%s/terraform/schedule", c.workspaceURL)
 }
@@ -135,11 +179,12 @@ func (c *Client) UpdateSchedule(ctx context.Context, id string, req *ScheduleReq
 	return c.writeSchedule(ctx, "POST", fmt.Sprintf("%s/update/%s", c.scheduleAPI(), id), req)
 }
 
-// GetSchedule reads a schedule. It returns (nil, nil) when the schedule no
-// longer exists so callers can drop it from Terraform state.
+// GetSchedule reads a schedule, asking for the full view including the
+// pattern, users, teams and endpoints. It returns (nil, nil) when the schedule
+// no longer exists so callers can drop it from Terraform state.
 func (c *Client) GetSchedule(ctx context.Context, id string) (*ScheduleResponse, error) {
 	tflog.Debug(ctx, "GetSchedule called", map[string]interface{}{"id": id})
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/read/%s", c.scheduleAPI(), id), nil)
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/read/%s?view=full", c.scheduleAPI(), id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -187,3 +232,281 @@ func (c *Client) DeleteSchedule(ctx context.Context, id, name string) (bool, err
 	}
 	return true, nil
 }
+
+// ScheduleWindow is one interval during which a schedule applies. Start is
+// inclusive and End exclusive.
+type ScheduleWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// scheduleHorizonDays bounds how far NextWindows searches. Four years covers
+// every monthly pattern, including ones that only match on the 29th of
+// February.
+const scheduleHorizonDays = 4*366 + 1
+
+// scheduleEvaluator is a ScheduleRequest with its timezone and timestamps
+// parsed, ready to answer which days and times it covers.
+type scheduleEvaluator struct {
+	req       *ScheduleRequest
+	loc       *time.Location
+	weekdays  map[time.Weekday]bool
+	dates     map[string]bool
+	lastDate  time.Time
+	expiresAt time.Time
+	rule      *recurrenceRule
+	excluded  map[string]bool
+	holidays  []*holidayEvent
+
+	// timeWindows are the request's windows sorted by start, or its single
+	// legacy window; timeWindowDays holds each one's weekday filter, nil
+	// when it applies every day.
+	timeWindows    []ScheduleTimeWindow
+	timeWindowDays []map[time.Weekday]bool
+}
+
+var weekdaysByName = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+func newScheduleEvaluator(req *ScheduleRequest) (*scheduleEvaluator, error) {
+	e := &scheduleEvaluator{req: req, loc: time.UTC, weekdays: map[time.Weekday]bool{}, dates: map[string]bool{}}
+	if req.Timezone != "" {
+		loc, err := time.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", req.Timezone, err)
+		}
+		e.loc = loc
+	}
+	if req.ExpiresAt != nil && *req.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiresAt %q: %w", *req.ExpiresAt, err)
+		}
+		e.expiresAt = t
+	}
+
+	switch req.ScheduleType {
+	case "weekdays":
+		for d := time.Monday; d <= time.Friday; d++ {
+			e.weekdays[d] = true
+		}
+	case "weekends":
+		e.weekdays[time.Saturday], e.weekdays[time.Sunday] = true, true
+	case "everyday":
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			e.weekdays[d] = true
+		}
+	case "custom":
+		for _, name := range req.Weekdays {
+			d, ok := weekdaysByName[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday %q", name)
+			}
+			e.weekdays[d] = true
+		}
+	case "monthly":
+		if req.StartDay < 1 || req.StartDay > 31 || req.EndDay < 1 || req.EndDay > 31 {
+			return nil, fmt.Errorf("monthly schedules need startDay and endDay between 1 and 31, got %d and %d", req.StartDay, req.EndDay)
+		}
+	case "specific":
+		for _, s := range req.SpecificDates {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid specific date %q: %w", s, err)
+			}
+			t = t.In(e.loc)
+			e.dates[t.Format("2006-01-02")] = true
+			if day := e.midnight(t); day.After(e.lastDate) {
+				e.lastDate = day
+			}
+		}
+	case "rrule":
+		rule, err := parseRecurrenceRule(req.RRule, e.loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rrule: %w", err)
+		}
+		e.rule = rule
+	default:
+		return nil, fmt.Errorf("unknown schedule type %q", req.ScheduleType)
+	}
+
+	e.timeWindows = append([]ScheduleTimeWindow(nil), req.Windows...)
+	if len(e.timeWindows) == 0 {
+		e.timeWindows = []ScheduleTimeWindow{{StartHour: req.StartHour, StartMinute: req.StartMinute, EndHour: req.EndHour, EndMinute: req.EndMinute}}
+	}
+	sort.SliceStable(e.timeWindows, func(i, j int) bool {
+		a, b := e.timeWindows[i], e.timeWindows[j]
+		return a.StartHour*60+a.StartMinute < b.StartHour*60+b.StartMinute
+	})
+	for _, tw := range e.timeWindows {
+		if tw.StartHour < 0 || tw.StartHour > 23 || tw.StartMinute < 0 || tw.StartMinute > 59 ||
+			tw.EndHour < 0 || tw.EndHour > 24 || tw.EndMinute < 0 || tw.EndMinute > 59 || tw.EndHour == 24 && tw.EndMinute != 0 {
+			return nil, fmt.Errorf("invalid window %02d:%02d-%02d:%02d", tw.StartHour, tw.StartMinute, tw.EndHour, tw.EndMinute)
+		}
+		var days map[time.Weekday]bool
+		for _, name := range tw.Weekdays {
+			d, ok := weekdaysByName[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("invalid window weekday %q", name)
+			}
+			if days == nil {
+				days = map[time.Weekday]bool{}
+			}
+			days[d] = true
+		}
+		e.timeWindowDays = append(e.timeWindowDays, days)
+	}
+
+	e.excluded = map[string]bool{}
+	for _, s := range req.ExcludeDates {
+		if err := ValidateExcludeDate(s); err != nil {
+			return nil, fmt.Errorf("invalid exclude date: %w", err)
+		}
+		e.excluded[s] = true
+	}
+	if req.HolidayCalendar != "" {
+		holidays, err := parseHolidayCalendar(req.HolidayCalendar, e.loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday calendar: %w", err)
+		}
+		e.holidays = holidays
+	}
+	return e, nil
+}
+
+// midnight returns the start of t's calendar day in the schedule timezone.
+func (e *scheduleEvaluator) midnight(t time.Time) time.Time {
+	t = t.In(e.loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, e.loc)
+}
+
+// matchesDay reports whether the schedule has a window starting on day.
+// Excluded dates and holidays remove the window starting that day.
+func (e *scheduleEvaluator) matchesDay(day time.Time) bool {
+	if e.excluded[day.Format("2006-01-02")] {
+		return false
+	}
+	for _, h := range e.holidays {
+		if h.covers(day) {
+			return false
+		}
+	}
+	switch e.req.ScheduleType {
+	case "monthly":
+		d := day.Day()
+		if e.req.StartDay <= e.req.EndDay {
+			return d >= e.req.StartDay && d <= e.req.EndDay
+		}
+		// The range wraps into the next month, such as 25 to 5.
+		return d >= e.req.StartDay || d <= e.req.EndDay
+	case "specific":
+		return e.dates[day.Format("2006-01-02")]
+	case "rrule":
+		return e.rule.matches(day)
+	}
+	return e.weekdays[day.Weekday()]
+}
+
+// windowsOn returns the windows starting on day, which must match, in order
+// of start. Times of day are wall-clock times in the schedule timezone, so a
+// window keeps its local hours across DST changes and an all-day window lasts
+// 23 or 25 hours on transition days. A window whose end is not after its
+// start runs past midnight into the next day.
+func (e *scheduleEvaluator) windowsOn(day time.Time) []ScheduleWindow {
+	y, m, d := day.Date()
+	var windows []ScheduleWindow
+	if e.req.AllDay {
+		windows = []ScheduleWindow{{Start: day, End: time.Date(y, m, d+1, 0, 0, 0, 0, e.loc)}}
+	} else {
+		for i, tw := range e.timeWindows {
+			if days := e.timeWindowDays[i]; days != nil && !days[day.Weekday()] {
+				continue
+			}
+			w := ScheduleWindow{Start: time.Date(y, m, d, tw.StartHour, tw.StartMinute, 0, 0, e.loc)}
+			endDay := d
+			if tw.EndHour*60+tw.EndMinute <= tw.StartHour*60+tw.StartMinute {
+				endDay++
+			}
+			w.End = time.Date(y, m, endDay, tw.EndHour, tw.EndMinute, 0, 0, e.loc)
+			windows = append(windows, w)
+		}
+	}
+
+	out := windows[:0]
+	for _, w := range windows {
+		if !e.expiresAt.IsZero() {
+			if !w.Start.Before(e.expiresAt) {
+				continue
+			}
+			if w.End.After(e.expiresAt) {
+				w.End = e.expiresAt.In(e.loc)
+			}
+		}
+		if w.End.After(w.Start) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// IsOpenAt reports whether the schedule applies at t.
+func (r *ScheduleRequest) IsOpenAt(t time.Time) (bool, error) {
+	e, err := newScheduleEvaluator(r)
+	if err != nil {
+		return false, err
+	}
+	if r.IsActive != nil && !*r.IsActive {
+		return false, nil
+	}
+	// Windows last at most a day, so only ones starting today or yesterday
+	// can contain t.
+	today := e.midnight(t)
+	y, m, d := today.Date()
+	for _, day := range []time.Time{time.Date(y, m, d-1, 0, 0, 0, 0, e.loc), today} {
+		if !e.matchesDay(day) {
+			continue
+		}
+		for _, w := range e.windowsOn(day) {
+			if !t.Before(w.Start) && t.Before(w.End) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// NextWindows returns up to n windows that end after from, in order. A
+// window already open at from is included with its actual start. Each
+// matching day yields its own window, even when consecutive windows touch.
+func (r *ScheduleRequest) NextWindows(from time.Time, n int) ([]ScheduleWindow, error) {
+	e, err := newScheduleEvaluator(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.IsActive != nil && !*r.IsActive {
+		return nil, nil
+	}
+
+	var windows []ScheduleWindow
+	y, m, d := e.midnight(from).Date()
+	for i := -1; i <= scheduleHorizonDays && len(windows) < n; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, e.loc)
+		if !e.expiresAt.IsZero() && !day.Before(e.expiresAt) {
+			break
+		}
+		if r.ScheduleType == "specific" && day.After(e.lastDate) {
+			break
+		}
+		if !e.matchesDay(day) {
+			continue
+		}
+		for _, w := range e.windowsOn(day) {
+			if w.End.After(from) && len(windows) < n {
+				windows = append(windows, w)
+			}
+		}
+	}
+	return windows, nil
+}
@@ -0,0 +1,627 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrenceRule is the day-level subset of an RFC 5545 recurrence rule a
+// schedule supports. Times of day come from the schedule's start and end
+// fields, so the rule only decides which days have a window.
+type recurrenceRule struct {
+	start      time.Time
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []recurrenceWeekday
+	byMonthDay []int
+	byMonth    []int
+	bySetPos   []int
+	wkst       time.Weekday
+
+	occurrences map[string]bool
+	// next is the index of the next period to expand; done is set once COUNT
+	// or UNTIL is reached.
+	next    int
+	emitted int
+	done    bool
+}
+
+// recurrenceWeekday is a BYDAY entry such as MO, 1MO or -1FR. A zero ordinal
+// matches every such weekday in the period.
+type recurrenceWeekday struct {
+	ordinal int
+	weekday time.Weekday
+}
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+const dateLayout = "2006-01-02"
+
+// ParseRecurrenceRule validates an RFC 5545 recurrence: a DTSTART line and an
+// RRULE line, such as
+//
+//	DTSTART:20250106
+//	RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO
+//
+// The "RRULE:" prefix may be omitted. Dates are interpreted in loc.
+func ParseRecurrenceRule(s string, loc *time.Location) error {
+	_, err := parseRecurrenceRule(s, loc)
+	return err
+}
+
+func parseRecurrenceRule(s string, loc *time.Location) (*recurrenceRule, error) {
+	r := &recurrenceRule{interval: 1, wkst: time.Monday, occurrences: map[string]bool{}}
+	var rule string
+	for _, line := range unfoldContentLines(s) {
+		name, params, value := splitContentLine(line)
+		switch name {
+		case "DTSTART":
+			start, err := parseICalDate(params, value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("DTSTART: %w", err)
+			}
+			r.start = start
+		case "RRULE":
+			rule = value
+		default:
+			if strings.Contains(line, "FREQ=") && rule == "" {
+				rule = line
+				continue
+			}
+			return nil, fmt.Errorf("unexpected line %q; expected DTSTART and RRULE", line)
+		}
+	}
+	if r.start.IsZero() {
+		return nil, fmt.Errorf("missing DTSTART line, such as DTSTART:20250106")
+	}
+	if rule == "" {
+		return nil, fmt.Errorf("missing RRULE line, such as RRULE:FREQ=WEEKLY;BYDAY=MO")
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("malformed rule part %q", part)
+		}
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return nil, fmt.Errorf("%s is given more than once", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.freq = strings.ToUpper(value)
+			switch r.freq {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+			case "HOURLY", "MINUTELY", "SECONDLY":
+				return nil, fmt.Errorf("FREQ=%s is not supported; a schedule has one window per day, timed by its start and end fields", r.freq)
+			default:
+				return nil, fmt.Errorf("invalid FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.interval, err = recurrenceInt(key, value, 1, 1000)
+		case "COUNT":
+			r.count, err = recurrenceInt(key, value, 1, 100000)
+		case "UNTIL":
+			r.until, err = parseICalDate("", value, loc)
+		case "WKST":
+			wd, ok := recurrenceWeekdays[strings.ToUpper(value)]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST %q", value)
+			}
+			r.wkst = wd
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				v = strings.ToUpper(v)
+				if len(v) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				wd, ok := recurrenceWeekdays[v[len(v)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				entry := recurrenceWeekday{weekday: wd}
+				if n := v[:len(v)-2]; n != "" {
+					if entry.ordinal, err = strconv.Atoi(n); err != nil || entry.ordinal == 0 || entry.ordinal < -53 || entry.ordinal > 53 {
+						return nil, fmt.Errorf("invalid BYDAY ordinal in %q", v)
+					}
+				}
+				r.byDay = append(r.byDay, entry)
+			}
+		case "BYMONTHDAY":
+			r.byMonthDay, err = recurrenceInts(key, value, 31, true)
+		case "BYMONTH":
+			r.byMonth, err = recurrenceInts(key, value, 12, false)
+		case "BYSETPOS":
+			r.bySetPos, err = recurrenceInts(key, value, 366, true)
+		case "BYHOUR", "BYMINUTE", "BYSECOND":
+			return nil, fmt.Errorf("%s is not supported; the window's time of day comes from the schedule's start and end fields", key)
+		case "BYYEARDAY", "BYWEEKNO":
+			return nil, fmt.Errorf("%s is not supported", key)
+		default:
+			return nil, fmt.Errorf("unknown rule part %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case r.freq == "":
+		return nil, fmt.Errorf("FREQ is required")
+	case r.count > 0 && !r.until.IsZero():
+		return nil, fmt.Errorf("COUNT and UNTIL cannot both be set")
+	case r.freq == "WEEKLY" && len(r.byMonthDay) > 0:
+		return nil, fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	case len(r.bySetPos) > 0 && len(r.byDay) == 0 && len(r.byMonthDay) == 0 && len(r.byMonth) == 0:
+		return nil, fmt.Errorf("BYSETPOS needs another BYxxx part to select from")
+	}
+	for _, d := range r.byDay {
+		if d.ordinal == 0 {
+			continue
+		}
+		if r.freq != "MONTHLY" && !(r.freq == "YEARLY" && len(r.byMonth) > 0) {
+			return nil, fmt.Errorf("BYDAY ordinals such as 1MO are only supported with FREQ=MONTHLY, or FREQ=YEARLY with BYMONTH")
+		}
+		if d.ordinal < -5 || d.ordinal > 5 {
+			return nil, fmt.Errorf("BYDAY ordinal %d is out of range for a month", d.ordinal)
+		}
+	}
+	return r, nil
+}
+
+func recurrenceInt(key, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s must be an integer between %d and %d, got %q", key, min, max, value)
+	}
+	return n, nil
+}
+
+func recurrenceInts(key, value string, max int, allowNegative bool) ([]int, error) {
+	var out []int
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n == 0 || n > max || n < -max || (!allowNegative && n < 0) {
+			return nil, fmt.Errorf("invalid %s value %q", key, v)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// matches reports whether day, a midnight in the rule's location, is an
+// occurrence.
+func (r *recurrenceRule) matches(day time.Time) bool {
+	if day.Before(r.start) {
+		return false
+	}
+	for !r.done && !r.periodStart(r.next).After(day) {
+		r.expandPeriod(r.next)
+		r.next++
+	}
+	return r.occurrences[day.Format(dateLayout)]
+}
+
+func (r *recurrenceRule) periodStart(i int) time.Time {
+	y, m, d := r.start.Date()
+	loc := r.start.Location()
+	switch r.freq {
+	case "DAILY":
+		return time.Date(y, m, d+i*r.interval, 0, 0, 0, 0, loc)
+	case "WEEKLY":
+		back := (int(r.start.Weekday()) - int(r.wkst) + 7) % 7
+		return time.Date(y, m, d-back+7*i*r.interval, 0, 0, 0, 0, loc)
+	case "MONTHLY":
+		return time.Date(y, m+time.Month(i*r.interval), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(y+i*r.interval, 1, 1, 0, 0, 0, 0, loc)
+	}
+}
+
+func (r *recurrenceRule) expandPeriod(i int) {
+	start := r.periodStart(i)
+	var candidates []time.Time
+	switch r.freq {
+	case "DAILY":
+		if r.dayFilters(start) {
+			candidates = []time.Time{start}
+		}
+	case "WEEKLY":
+		y, m, d := start.Date()
+		for k := 0; k < 7; k++ {
+			day := time.Date(y, m, d+k, 0, 0, 0, 0, start.Location())
+			if r.weeklyDay(day) && r.monthAllowed(day.Month()) {
+				candidates = append(candidates, day)
+			}
+		}
+	case "MONTHLY":
+		if r.monthAllowed(start.Month()) {
+			candidates = r.monthCandidates(start.Year(), start.Month())
+		}
+	case "YEARLY":
+		months := r.byMonth
+		if len(months) == 0 {
+			if len(r.byDay) > 0 || len(r.byMonthDay) > 0 {
+				months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			} else {
+				months = []int{int(r.start.Month())}
+			}
+		}
+		sort.Ints(months)
+		for _, m := range months {
+			candidates = append(candidates, r.monthCandidates(start.Year(), time.Month(m))...)
+		}
+	}
+	candidates = r.applySetPos(candidates)
+
+	for _, day := range candidates {
+		if day.Before(r.start) {
+			continue
+		}
+		if !r.until.IsZero() && day.After(r.until) {
+			r.done = true
+			return
+		}
+		r.occurrences[day.Format(dateLayout)] = true
+		r.emitted++
+		if r.count > 0 && r.emitted >= r.count {
+			r.done = true
+			return
+		}
+	}
+	if !r.until.IsZero() && r.periodStart(i+1).After(r.until) {
+		r.done = true
+	}
+}
+
+// dayFilters applies BYMONTH, BYMONTHDAY and BYDAY to a DAILY candidate.
+func (r *recurrenceRule) dayFilters(day time.Time) bool {
+	if !r.monthAllowed(day.Month()) {
+		return false
+	}
+	if len(r.byMonthDay) > 0 && !containsDay(r.resolveMonthDays(day.Year(), day.Month()), day.Day()) {
+		return false
+	}
+	if len(r.byDay) > 0 {
+		for _, wd := range r.byDay {
+			if wd.weekday == day.Weekday() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r *recurrenceRule) weeklyDay(day time.Time) bool {
+	if len(r.byDay) == 0 {
+		return day.Weekday() == r.start.Weekday()
+	}
+	for _, wd := range r.byDay {
+		if wd.weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recurrenceRule) monthAllowed(m time.Month) bool {
+	if len(r.byMonth) == 0 {
+		return true
+	}
+	for _, v := range r.byMonth {
+		if time.Month(v) == m {
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func (r *recurrenceRule) resolveMonthDays(y int, m time.Month) []int {
+	n := daysIn(y, m)
+	var days []int
+	for _, v := range r.byMonthDay {
+		if v < 0 {
+			v = n + 1 + v
+		}
+		if v >= 1 && v <= n {
+			days = append(days, v)
+		}
+	}
+	return days
+}
+
+func containsDay(days []int, d int) bool {
+	for _, v := range days {
+		if v == d {
+			return true
+		}
+	}
+	return false
+}
+
+// monthCandidates returns the days of y-m selected by BYMONTHDAY and BYDAY,
+// or the start's day of month when neither is given.
+func (r *recurrenceRule) monthCandidates(y int, m time.Month) []time.Time {
+	loc := r.start.Location()
+	n := daysIn(y, m)
+	var days []int
+	switch {
+	case len(r.byDay) == 0 && len(r.byMonthDay) == 0:
+		if d := r.start.Day(); d <= n {
+			days = []int{d}
+		}
+	case len(r.byDay) == 0:
+		days = r.resolveMonthDays(y, m)
+	default:
+		for d := 1; d <= n; d++ {
+			if r.byDayInMonth(y, m, d) && (len(r.byMonthDay) == 0 || containsDay(r.resolveMonthDays(y, m), d)) {
+				days = append(days, d)
+			}
+		}
+	}
+	sort.Ints(days)
+	out := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		out = append(out, time.Date(y, m, d, 0, 0, 0, 0, loc))
+	}
+	return out
+}
+
+func (r *recurrenceRule) byDayInMonth(y int, m time.Month, d int) bool {
+	wd := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Weekday()
+	n := daysIn(y, m)
+	for _, entry := range r.byDay {
+		if entry.weekday != wd {
+			continue
+		}
+		switch {
+		case entry.ordinal == 0:
+			return true
+		case entry.ordinal > 0 && (d-1)/7+1 == entry.ordinal:
+			return true
+		case entry.ordinal < 0 && (n-d)/7+1 == -entry.ordinal:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recurrenceRule) applySetPos(candidates []time.Time) []time.Time {
+	if len(r.bySetPos) == 0 {
+		return candidates
+	}
+	var out []time.Time
+	for _, p := range r.bySetPos {
+		i := p - 1
+		if p < 0 {
+			i = len(candidates) + p
+		}
+		if i >= 0 && i < len(candidates) {
+			out = append(out, candidates[i])
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Before(out[b]) })
+	return out
+}
+
+// unfoldContentLines splits iCalendar text into logical lines, joining folded
+// continuation lines and dropping blank ones.
+func unfoldContentLines(s string) []string {
+	var lines []string
+	for _, raw := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+			continue
+		}
+		if raw = strings.TrimSpace(raw); raw != "" {
+			lines = append(lines, raw)
+		}
+	}
+	return lines
+}
+
+// splitContentLine splits "NAME;PARAM=X:VALUE" into its name, parameters and
+// value.
+func splitContentLine(line string) (name, params, value string) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", line
+	}
+	name, params, _ = strings.Cut(head, ";")
+	return strings.ToUpper(name), params, value
+}
+
+// parseICalDate parses an iCalendar DATE or DATE-TIME value and returns the
+// midnight of its day in loc. UTC date-times ("Z") are converted to loc, and
+// a TZID parameter is honoured; floating values are taken as written.
+func parseICalDate(params, value string, loc *time.Location) (time.Time, error) {
+	src := loc
+	for _, p := range strings.Split(params, ";") {
+		if k, v, ok := strings.Cut(p, "="); ok && strings.EqualFold(k, "TZID") {
+			l, err := time.LoadLocation(v)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("unknown TZID %q", v)
+			}
+			src = l
+		}
+	}
+	var t time.Time
+	var err error
+	switch {
+	case len(value) == 8:
+		t, err = time.ParseInLocation("20060102", value, loc)
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse("20060102T150405Z", value)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", value, src)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q; use YYYYMMDD or YYYYMMDDTHHMMSS[Z]", value)
+	}
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc), nil
+}
+
+// holidayEvent is one VEVENT of a holiday calendar: the days from start up
+// to end, repeating by rule when one is given.
+type holidayEvent struct {
+	start   time.Time
+	days    int
+	rule    *recurrenceRule
+	exdates map[string]bool
+}
+
+// ParseHolidayCalendar validates iCalendar (RFC 5545) content whose VEVENTs
+// are the holidays to exclude from a schedule.
+func ParseHolidayCalendar(s string, loc *time.Location) error {
+	_, err := parseHolidayCalendar(s, loc)
+	return err
+}
+
+func parseHolidayCalendar(s string, loc *time.Location) ([]*holidayEvent, error) {
+	var events []*holidayEvent
+	var cur *holidayEvent
+	var end time.Time
+	var rule string
+	inCalendar := false
+	for _, line := range unfoldContentLines(s) {
+		name, params, value := splitContentLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCALENDAR"):
+			inCalendar = true
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			cur, end, rule = &holidayEvent{days: 1, exdates: map[string]bool{}}, time.Time{}, ""
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if cur == nil {
+				return nil, fmt.Errorf("END:VEVENT without BEGIN:VEVENT")
+			}
+			if cur.start.IsZero() {
+				return nil, fmt.Errorf("VEVENT %d has no DTSTART", len(events)+1)
+			}
+			if !end.IsZero() {
+				// DTEND is exclusive.
+				if cur.days = int(end.Sub(cur.start).Hours()+12) / 24; cur.days < 1 {
+					cur.days = 1
+				}
+			}
+			if rule != "" {
+				r, err := parseRecurrenceRule("DTSTART:"+cur.start.Format("20060102")+"\nRRULE:"+rule, loc)
+				if err != nil {
+					return nil, fmt.Errorf("VEVENT %d: %w", len(events)+1, err)
+				}
+				cur.rule = r
+			}
+			events = append(events, cur)
+			cur = nil
+		case cur == nil:
+			continue
+		case name == "DTSTART":
+			t, err := parseICalDate(params, value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("VEVENT %d DTSTART: %w", len(events)+1, err)
+			}
+			cur.start = t
+		case name == "DTEND":
+			t, err := parseICalDate(params, value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("VEVENT %d DTEND: %w", len(events)+1, err)
+			}
+			end = t
+		case name == "RRULE":
+			rule = value
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				t, err := parseICalDate(params, v, loc)
+				if err != nil {
+					return nil, fmt.Errorf("VEVENT %d EXDATE: %w", len(events)+1, err)
+				}
+				cur.exdates[t.Format(dateLayout)] = true
+			}
+		}
+	}
+	if !inCalendar {
+		return nil, fmt.Errorf("not an iCalendar file: missing BEGIN:VCALENDAR")
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("VEVENT %d is not closed with END:VEVENT", len(events)+1)
+	}
+	return events, nil
+}
+
+// covers reports whether day falls on an occurrence of the event.
+func (h *holidayEvent) covers(day time.Time) bool {
+	y, m, d := day.Date()
+	for back := 0; back < h.days; back++ {
+		first := time.Date(y, m, d-back, 0, 0, 0, 0, day.Location())
+		if h.exdates[first.Format(dateLayout)] {
+			continue
+		}
+		if h.rule == nil {
+			if first.Equal(h.start) {
+				return true
+			}
+		} else if h.rule.matches(first) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateExcludeDate checks a schedule exclude_dates entry, a YYYY-MM-DD
+// calendar date.
+func ValidateExcludeDate(s string) error {
+	if _, err := time.Parse(dateLayout, s); err != nil {
+		return fmt.Errorf("%q is not a YYYY-MM-DD date", s)
+	}
+	return nil
+}
+
+// UsesLocalExpansion reports whether the schedule needs features the backend
+// lacks natively, a recurrence rule or excluded days, and so is sent as the
+// specific dates ExpandRecurrence computes.
+func (r *ScheduleRequest) UsesLocalExpansion() bool {
+	return r.ScheduleType == "rrule" || len(r.ExcludeDates) > 0 || r.HolidayCalendar != ""
+}
+
+// ExpandRecurrence returns a copy of the request as a "specific" schedule
+// listing every day with a window from the day of from through horizon days
+// later. The time-of-day fields are kept.
+func (r *ScheduleRequest) ExpandRecurrence(from time.Time, horizon int) (*ScheduleRequest, error) {
+	e, err := newScheduleEvaluator(r)
+	if err != nil {
+		return nil, err
+	}
+	out := *r
+	out.ScheduleType = "specific"
+	out.Weekdays, out.StartDay, out.EndDay = nil, 0, 0
+	out.SpecificDates = []string{}
+
+	y, m, d := e.midnight(from).Date()
+	for i := 0; i <= horizon; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, e.loc)
+		if !e.expiresAt.IsZero() && !day.Before(e.expiresAt) {
+			break
+		}
+		if e.matchesDay(day) {
+			out.SpecificDates = append(out.SpecificDates, day.Format(time.RFC3339))
+		}
+	}
+	return &out, nil
+}
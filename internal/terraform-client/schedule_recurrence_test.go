@@ -0,0 +1,171 @@
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func windowDates(t *testing.T, req ScheduleRequest, from string, n int) []string {
+	t.Helper()
+	req.AllDay = true
+	windows, err := req.NextWindows(mustTime(t, from), n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dates := []string{}
+	for _, w := range windows {
+		dates = append(dates, w.Start.Format(dateLayout))
+	}
+	return dates
+}
+
+const testHolidays = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+SUMMARY:Christmas Day
+DTSTART;VALUE=DATE:20201225
+RRULE:FREQ=YEARLY
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Company
+  shutdown
+DTSTART;VALUE=DATE:20251226
+DTEND;VALUE=DATE:20251228
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestScheduleRecurrence(t *testing.T) {
+	tests := []struct {
+		name string
+		req  ScheduleRequest
+		from string
+		n    int
+		want []string
+	}{
+		{
+			name: "every other monday, UTC DTSTART in Berlin",
+			req:  ScheduleRequest{ScheduleType: "rrule", Timezone: "Europe/Berlin", RRule: "DTSTART:20250105T230000Z\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO"},
+			from: "2025-01-07T00:00:00Z",
+			n:    3,
+			want: []string{"2025-01-20", "2025-02-03", "2025-02-17"},
+		},
+		{
+			name: "last friday of the month",
+			req:  ScheduleRequest{ScheduleType: "rrule", RRule: "DTSTART:20250101\nRRULE:FREQ=MONTHLY;BYDAY=-1FR"},
+			from: "2025-01-01T00:00:00Z",
+			n:    3,
+			want: []string{"2025-01-31", "2025-02-28", "2025-03-28"},
+		},
+		{
+			name: "last weekday of the month",
+			req:  ScheduleRequest{ScheduleType: "rrule", RRule: "DTSTART:20250501\nFREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1"},
+			from: "2025-05-01T00:00:00Z",
+			n:    2,
+			want: []string{"2025-05-30", "2025-06-30"},
+		},
+		{
+			name: "fourth thursday of november",
+			req:  ScheduleRequest{ScheduleType: "rrule", RRule: "DTSTART:20250101\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH"},
+			from: "2025-01-01T00:00:00Z",
+			n:    2,
+			want: []string{"2025-11-27", "2026-11-26"},
+		},
+		{
+			name: "count stops the rule",
+			req:  ScheduleRequest{ScheduleType: "rrule", RRule: "DTSTART:20250601\nRRULE:FREQ=DAILY;COUNT=3"},
+			from: "2025-06-02T00:00:00Z",
+			n:    5,
+			want: []string{"2025-06-02", "2025-06-03"},
+		},
+		{
+			name: "until is inclusive",
+			req:  ScheduleRequest{ScheduleType: "rrule", RRule: "DTSTART:20250601\nRRULE:FREQ=WEEKLY;BYDAY=SA;UNTIL=20250614"},
+			from: "2025-06-01T00:00:00Z",
+			n:    5,
+			want: []string{"2025-06-07", "2025-06-14"},
+		},
+		{
+			name: "exclude dates",
+			req:  ScheduleRequest{ScheduleType: "weekdays", ExcludeDates: []string{"2025-06-10"}},
+			from: "2025-06-09T00:00:00Z",
+			n:    2,
+			want: []string{"2025-06-09", "2025-06-11"},
+		},
+		{
+			name: "holiday calendar",
+			req:  ScheduleRequest{ScheduleType: "weekdays", HolidayCalendar: testHolidays},
+			from: "2025-12-24T00:00:00Z",
+			n:    3,
+			want: []string{"2025-12-24", "2025-12-29", "2025-12-30"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windowDates(t, tt.req, tt.from, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRuleErrors(t *testing.T) {
+	tests := map[string]string{
+		"RRULE:FREQ=DAILY":                                          "DTSTART",
+		"DTSTART:20250101":                                          "RRULE",
+		"DTSTART:20250101\nRRULE:FREQ=HOURLY":                       "not supported",
+		"DTSTART:20250101\nRRULE:FREQ=DAILY;BYHOUR=9":               "start and end fields",
+		"DTSTART:20250101\nRRULE:FREQ=DAILY;COUNT=2;UNTIL=20250301": "cannot both",
+		"DTSTART:20250101\nRRULE:FREQ=WEEKLY;BYDAY=1MO":             "ordinals",
+		"DTSTART:20250101\nRRULE:FREQ=MONTHLY;BYMONTHDAY=32":        "BYMONTHDAY",
+		"DTSTART:2025-01-01\nRRULE:FREQ=DAILY":                      "invalid date",
+	}
+	for in, want := range tests {
+		err := ParseRecurrenceRule(in, time.UTC)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: error = %v, want one containing %q", in, err, want)
+		}
+	}
+
+	if err := ParseHolidayCalendar("BEGIN:VEVENT\nDTSTART:20250101\nEND:VEVENT", time.UTC); err == nil {
+		t.Error("expected content without VCALENDAR to be rejected")
+	}
+	if err := ParseHolidayCalendar("BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\nEND:VCALENDAR", time.UTC); err == nil {
+		t.Error("expected a VEVENT without DTSTART to be rejected")
+	}
+}
+
+func TestScheduleExpandRecurrence(t *testing.T) {
+	req := &ScheduleRequest{
+		Name:         "biweekly",
+		ScheduleType: "rrule",
+		StartHour:    9,
+		EndHour:      17,
+		RRule:        "DTSTART:20250106\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+		ExcludeDates: []string{"2025-01-20"},
+	}
+	if !req.UsesLocalExpansion() {
+		t.Fatal("expected an rrule schedule to be expanded locally")
+	}
+	got, err := req.ExpandRecurrence(mustTime(t, "2025-01-07T12:00:00Z"), 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ScheduleType != "specific" || got.StartHour != 9 || got.EndHour != 17 {
+		t.Errorf("expanded request = %+v", got)
+	}
+	if want := []string{"2025-02-03T00:00:00Z"}; !reflect.DeepEqual(got.SpecificDates, want) {
+		t.Errorf("specific dates = %v, want %v", got.SpecificDates, want)
+	}
+
+	body, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "DTSTART") || strings.Contains(string(body), "2025-01-20") {
+		t.Errorf("local-only fields were sent: %s", body)
+	}
+}
@@ -0,0 +1,255 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestScheduleNextWindows(t *testing.T) {
+	tests := []struct {
+		name string
+		req  ScheduleRequest
+		from string
+		n    int
+		want [][2]string
+	}{
+		{
+			name: "weekdays from a saturday",
+			req:  ScheduleRequest{ScheduleType: "weekdays", StartHour: 9, EndHour: 17, Timezone: "America/New_York"},
+			from: "2025-06-07T12:00:00-04:00",
+			n:    2,
+			want: [][2]string{
+				{"2025-06-09T09:00:00-04:00", "2025-06-09T17:00:00-04:00"},
+				{"2025-06-10T09:00:00-04:00", "2025-06-10T17:00:00-04:00"},
+			},
+		},
+		{
+			name: "window already open keeps its start",
+			req:  ScheduleRequest{ScheduleType: "everyday", StartHour: 9, EndHour: 17},
+			from: "2025-06-09T12:00:00Z",
+			n:    1,
+			want: [][2]string{{"2025-06-09T09:00:00Z", "2025-06-09T17:00:00Z"}},
+		},
+		{
+			name: "weekends all day",
+			req:  ScheduleRequest{ScheduleType: "weekends", AllDay: true},
+			from: "2025-06-09T00:00:00Z",
+			n:    2,
+			want: [][2]string{
+				{"2025-06-14T00:00:00Z", "2025-06-15T00:00:00Z"},
+				{"2025-06-15T00:00:00Z", "2025-06-16T00:00:00Z"},
+			},
+		},
+		{
+			name: "split windows",
+			req: ScheduleRequest{ScheduleType: "weekdays", Windows: []ScheduleTimeWindow{
+				{StartHour: 18, EndHour: 24},
+				{StartHour: 0, EndHour: 6},
+			}},
+			from: "2025-06-06T12:00:00Z",
+			n:    3,
+			want: [][2]string{
+				{"2025-06-06T18:00:00Z", "2025-06-07T00:00:00Z"},
+				{"2025-06-09T00:00:00Z", "2025-06-09T06:00:00Z"},
+				{"2025-06-09T18:00:00Z", "2025-06-10T00:00:00Z"},
+			},
+		},
+		{
+			name: "window weekdays and overnight",
+			req: ScheduleRequest{ScheduleType: "everyday", Windows: []ScheduleTimeWindow{
+				{StartHour: 9, EndHour: 12, Weekdays: []string{"Monday"}},
+				{StartHour: 22, StartMinute: 30, EndHour: 2, Weekdays: []string{"Friday"}},
+			}},
+			from: "2025-06-09T10:00:00Z",
+			n:    2,
+			want: [][2]string{
+				{"2025-06-09T09:00:00Z", "2025-06-09T12:00:00Z"},
+				{"2025-06-13T22:30:00Z", "2025-06-14T02:00:00Z"},
+			},
+		},
+		{
+			name: "custom overnight window",
+			req:  ScheduleRequest{ScheduleType: "custom", Weekdays: []string{"Friday"}, StartHour: 22, EndHour: 6},
+			from: "2025-06-09T00:00:00Z",
+			n:    1,
+			want: [][2]string{{"2025-06-13T22:00:00Z", "2025-06-14T06:00:00Z"}},
+		},
+		{
+			name: "monthly range wrapping into the next month",
+			req:  ScheduleRequest{ScheduleType: "monthly", StartDay: 30, EndDay: 1, AllDay: true},
+			from: "2025-02-15T00:00:00Z",
+			n:    3,
+			want: [][2]string{
+				{"2025-03-01T00:00:00Z", "2025-03-02T00:00:00Z"},
+				{"2025-03-30T00:00:00Z", "2025-03-31T00:00:00Z"},
+				{"2025-03-31T00:00:00Z", "2025-04-01T00:00:00Z"},
+			},
+		},
+		{
+			name: "monthly on a day some months lack",
+			req:  ScheduleRequest{ScheduleType: "monthly", StartDay: 31, EndDay: 31, StartHour: 1, EndHour: 2},
+			from: "2025-04-01T00:00:00Z",
+			n:    2,
+			want: [][2]string{
+				{"2025-05-31T01:00:00Z", "2025-05-31T02:00:00Z"},
+				{"2025-07-31T01:00:00Z", "2025-07-31T02:00:00Z"},
+			},
+		},
+		{
+			name: "specific dates in the schedule timezone",
+			req: ScheduleRequest{ScheduleType: "specific", AllDay: true, Timezone: "Asia/Tokyo",
+				SpecificDates: []string{"2025-12-31T20:00:00Z", "2025-01-01T00:00:00Z"}},
+			from: "2025-06-01T00:00:00Z",
+			n:    5,
+			want: [][2]string{{"2026-01-01T00:00:00+09:00", "2026-01-02T00:00:00+09:00"}},
+		},
+		{
+			name: "expires_at clips and ends the schedule",
+			req:  ScheduleRequest{ScheduleType: "everyday", StartHour: 9, EndHour: 17, ExpiresAt: ptr("2025-06-10T12:00:00Z")},
+			from: "2025-06-09T00:00:00Z",
+			n:    5,
+			want: [][2]string{
+				{"2025-06-09T09:00:00Z", "2025-06-09T17:00:00Z"},
+				{"2025-06-10T09:00:00Z", "2025-06-10T12:00:00Z"},
+			},
+		},
+		{
+			name: "inactive",
+			req:  ScheduleRequest{ScheduleType: "everyday", AllDay: true, IsActive: ptr(false)},
+			from: "2025-06-09T00:00:00Z",
+			n:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.req.NextWindows(mustTime(t, tt.from), tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d windows %v, want %d", len(got), got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !got[i].Start.Equal(mustTime(t, w[0])) || !got[i].End.Equal(mustTime(t, w[1])) {
+					t.Errorf("window %d = %s - %s, want %s - %s", i, got[i].Start.Format(time.RFC3339), got[i].End.Format(time.RFC3339), w[0], w[1])
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleIsOpenAt(t *testing.T) {
+	overnight := ScheduleRequest{ScheduleType: "custom", Weekdays: []string{"Friday"}, StartHour: 22, StartMinute: 30, EndHour: 6, Timezone: "Europe/Berlin"}
+	business := ScheduleRequest{ScheduleType: "weekdays", StartHour: 9, EndHour: 17, Timezone: "America/New_York"}
+	split := ScheduleRequest{ScheduleType: "everyday", Windows: []ScheduleTimeWindow{{StartHour: 0, EndHour: 6}, {StartHour: 18, EndHour: 24}}}
+	tests := []struct {
+		req  ScheduleRequest
+		at   string
+		want bool
+	}{
+		{business, "2025-06-09T13:00:00Z", true},  // 09:00 EDT, inclusive start
+		{business, "2025-06-09T21:00:00Z", false}, // 17:00 EDT, exclusive end
+		{business, "2025-06-07T15:00:00Z", false}, // Saturday
+		{overnight, "2025-06-13T20:29:00Z", false},
+		{overnight, "2025-06-13T20:30:00Z", true},
+		{overnight, "2025-06-14T01:00:00Z", true}, // Saturday 03:00 in Berlin, still Friday's window
+		{overnight, "2025-06-14T04:00:00Z", false},
+		{split, "2025-06-09T05:59:00Z", true},
+		{split, "2025-06-09T12:00:00Z", false},
+		{split, "2025-06-09T23:59:00Z", true},
+		{ScheduleRequest{ScheduleType: "everyday", AllDay: true, ExpiresAt: ptr("2025-06-10T00:00:00Z")}, "2025-06-10T00:00:00Z", false},
+	}
+	for _, tt := range tests {
+		got, err := tt.req.IsOpenAt(mustTime(t, tt.at))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s %v at %s: got %v, want %v", tt.req.ScheduleType, tt.req.Weekdays, tt.at, got, tt.want)
+		}
+	}
+}
+
+// Windows are wall-clock times, so across a DST change they keep their local
+// hours and shift in UTC, and all-day windows stretch or shrink with the day.
+func TestScheduleDSTTransitions(t *testing.T) {
+	ny := ScheduleRequest{ScheduleType: "everyday", StartHour: 9, EndHour: 17, Timezone: "America/New_York"}
+	got, err := ny.NextWindows(mustTime(t, "2025-03-08T00:00:00-05:00"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Start.UTC().Hour() != 14 || got[1].Start.UTC().Hour() != 13 {
+		t.Errorf("spring forward: starts %s, %s; want 14:00 then 13:00 UTC", got[0].Start.UTC(), got[1].Start.UTC())
+	}
+	for _, w := range got {
+		if w.End.Sub(w.Start) != 8*time.Hour {
+			t.Errorf("spring forward: window %s lasts %s, want 8h", w.Start, w.End.Sub(w.Start))
+		}
+	}
+
+	allDay := ScheduleRequest{ScheduleType: "everyday", AllDay: true, Timezone: "America/New_York"}
+	for from, want := range map[string]time.Duration{
+		"2025-03-09T00:00:00-05:00": 23 * time.Hour,
+		"2025-11-02T00:00:00-04:00": 25 * time.Hour,
+	} {
+		got, err := allDay.NextWindows(mustTime(t, from), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := got[0].End.Sub(got[0].Start); d != want {
+			t.Errorf("all day from %s lasts %s, want %s", from, d, want)
+		}
+	}
+
+	// 01:00 to 03:00 on the fall-back night spans the repeated hour.
+	fallBack := ScheduleRequest{ScheduleType: "everyday", StartHour: 1, EndHour: 3, Timezone: "America/New_York"}
+	got, err = fallBack.NextWindows(mustTime(t, "2025-11-02T00:00:00-04:00"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := got[0].End.Sub(got[0].Start); d != 3*time.Hour {
+		t.Errorf("fall back window lasts %s, want 3h", d)
+	}
+	if open, _ := fallBack.IsOpenAt(mustTime(t, "2025-11-02T06:30:00Z")); !open {
+		t.Error("expected the second 01:30 of the fall-back night to be inside the window")
+	}
+
+	// Europe/London skips 01:00-02:00 on 2025-03-30; a 00:30-02:30 window
+	// lasts an hour less.
+	london := ScheduleRequest{ScheduleType: "everyday", StartHour: 0, StartMinute: 30, EndHour: 2, EndMinute: 30, Timezone: "Europe/London"}
+	got, err = london.NextWindows(mustTime(t, "2025-03-30T00:00:00Z"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := got[0].End.Sub(got[0].Start); d != time.Hour {
+		t.Errorf("spring forward window lasts %s, want 1h", d)
+	}
+}
+
+func TestScheduleEvaluatorErrors(t *testing.T) {
+	for _, req := range []ScheduleRequest{
+		{ScheduleType: "fortnightly"},
+		{ScheduleType: "everyday", Timezone: "Mars/Olympus_Mons"},
+		{ScheduleType: "custom", Weekdays: []string{"Funday"}},
+		{ScheduleType: "monthly", StartDay: 0, EndDay: 5},
+		{ScheduleType: "specific", SpecificDates: []string{"2025-01-01"}},
+		{ScheduleType: "everyday", ExpiresAt: ptr("tomorrow")},
+		{ScheduleType: "everyday", Windows: []ScheduleTimeWindow{{StartHour: 9, EndHour: 25}}},
+		{ScheduleType: "everyday", Windows: []ScheduleTimeWindow{{StartHour: 9, EndHour: 10, Weekdays: []string{"Funday"}}}},
+	} {
+		if _, err := req.NextWindows(time.Now(), 1); err == nil {
+			t.Errorf("%+v: expected an error", req)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// TeamMembershipRequest adds or removes a single member or endpoint from a
+// group without touching the rest of its lists.
+type TeamMembershipRequest struct {
+	Team     string `json:"team"`
+	Email    string `json:"email,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// TeamMembership is the current set of members and endpoints of a group.
+type TeamMembership struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Members   []string `json:"members"`
+	Endpoints []string `json:"endpoints"`
+}
+
+func (c *Client) writeTeamMembership(ctx context.Context, action string, req *TeamMembershipRequest) error {
+	payloadBuf := bytes.NewBuffer([]byte{})
+	if err := json.NewEncoder(payloadBuf).Encode(req); err != nil {
+		return fmt.Errorf("failed to json encode request body. err %w", err)
+	}
+
+	request, err := http.NewRequest("POST", fmt.Sprintf("%s/%s", c.teamAPI(), action), payloadBuf)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		tflog.Error(ctx, "Failed to make request to adaptive API for group membership", map[string]interface{}{
+			"action": action,
+			"team":   req.Team,
+			"error":  err.Error(),
+		})
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+	if msg, derr := decodeError(ctx, response); derr == nil && msg != "" {
+		return errors.New(msg)
+	}
+	tflog.Error(ctx, "Group membership change failed", map[string]interface{}{
+		"action":      action,
+		"team":        req.Team,
+		"status_code": response.StatusCode,
+	})
+	return fmt.Errorf("error updating membership of group %s (status %d)", req.Team, response.StatusCode)
+}
+
+// AddTeamMember adds a user to a group, leaving its other members in place.
+func (c *Client) AddTeamMember(ctx context.Context, team, email string) error {
+	tflog.Debug(ctx, "AddTeamMember called", map[string]interface{}{"team": team, "email": email})
+	return c.writeTeamMembership(ctx, "members/add", &TeamMembershipRequest{Team: team, Email: email})
+}
+
+// RemoveTeamMember removes a user from a group. Removing a user that is not a
+// member succeeds.
+func (c *Client) RemoveTeamMember(ctx context.Context, team, email string) error {
+	tflog.Debug(ctx, "RemoveTeamMember called", map[string]interface{}{"team": team, "email": email})
+	return c.writeTeamMembership(ctx, "members/remove", &TeamMembershipRequest{Team: team, Email: email})
+}
+
+// AddTeamEndpoint grants a group access to an endpoint, leaving its other
+// endpoints in place.
+func (c *Client) AddTeamEndpoint(ctx context.Context, team, endpoint string) error {
+	tflog.Debug(ctx, "AddTeamEndpoint called", map[string]interface{}{"team": team, "endpoint": endpoint})
+	return c.writeTeamMembership(ctx, "endpoints/add", &TeamMembershipRequest{Team: team, Endpoint: endpoint})
+}
+
+// RemoveTeamEndpoint revokes a group's access to an endpoint. Removing an
+// endpoint the group does not have succeeds.
+func (c *Client) RemoveTeamEndpoint(ctx context.Context, team, endpoint string) error {
+	tflog.Debug(ctx, "RemoveTeamEndpoint called", map[string]interface{}{"team": team, "endpoint": endpoint})
+	return c.writeTeamMembership(ctx, "endpoints/remove", &TeamMembershipRequest{Team: team, Endpoint: endpoint})
+}
+
+// GetTeamMembership reads a group's members and endpoints by group name. It
+// returns (nil, nil) when the group no longer exists.
+func (c *Client) GetTeamMembership(ctx context.Context, team string) (*TeamMembership, error) {
+	tflog.Debug(ctx, "GetTeamMembership called", map[string]interface{}{"team": team})
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/membership/%s", c.teamAPI(), url.PathEscape(team)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading membership of group %s (status %d)", team, response.StatusCode)
+	}
+
+	var resp TeamMembership
+	if err := json.NewDecoder(response.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response body. err %w", err)
+	}
+	return &resp, nil
+}